@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/tui/editor"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change the config file",
+	Long: `Boba Break reads its settings from a TOML file, config.toml in the
+boba-break directory of the user config dir (~/.config on Linux). Another
+file can be used with --config or $BOBA_BREAK_CONFIG.
+
+Every key can be overridden with an environment variable named after it,
+e.g. BOBA_BREAK_TIMER_FOCUS=50m for timer.focus.
+
+A project can commit a .boba-break.toml setting project, tags, profile,
+notes_dir and profiles for everyone working in it. The closest one in or
+above the working directory is laid over the config file.
+
+Keys: ` + strings.Join(config.Keys(), ", "),
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a config file with every key and its default",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		if err := config.Init(path, force); err != nil {
+			return fmt.Errorf("%w, use --force to replace it", err)
+		}
+		fmt.Println("Wrote", path)
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a key, after environment overrides and the project file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		c, _, err := config.LoadForDir(path, ".")
+		if err != nil {
+			return err
+		}
+		value, err := c.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a key in the config file",
+	Long: `Change a key in the config file, creating the file if there is none.
+Only the line of the key is touched, comments and the rest stay as they
+are. Nothing is written if the value isn't valid.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		return config.SetInFile(path, args[0], args[1])
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $VISUAL or $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := config.Init(path, false); err != nil {
+				return err
+			}
+		}
+		command := editor.Command()
+		c := exec.Command(command[0], append(command[1:], path)...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			return err
+		}
+		if _, err := config.Load(path); err != nil {
+			return fmt.Errorf("the config file has problems:\n%w", err)
+		}
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file, environment overrides and project file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		_, p, err := config.LoadForDir(path, ".")
+		if err != nil {
+			return err
+		}
+		fmt.Println(path, "is valid")
+		if p != nil {
+			fmt.Println(p.Path, "is valid")
+		}
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print where the config file is read from",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if projectFile, _ := cmd.Flags().GetBool("project"); projectFile {
+			path, ok := config.FindProject(".")
+			if !ok {
+				return fmt.Errorf("no %s in this directory or above it", config.ProjectFileName)
+			}
+			fmt.Println(path)
+			return nil
+		}
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd, configEditCmd, configValidateCmd, configPathCmd)
+	configInitCmd.Flags().Bool("force", false, "Replace an existing config file")
+	configPathCmd.Flags().Bool("project", false, "Print the project file in use instead")
+}
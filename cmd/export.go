@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the log export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the break log as JSON or CSV",
+	Long: `Write the break log to stdout, or to a file with --output, as JSON or CSV.
+Use --project, --tag or --task to only export matching entries.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		entries := filterFromFlags(cmd).Apply(logger.Entries())
+
+		var out io.Writer = os.Stdout
+		if output != "" {
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		switch format {
+		case "json":
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		case "csv":
+			return writeCSV(out, entries)
+		default:
+			return fmt.Errorf("unknown format %q, expected json or csv", format)
+		}
+	},
+}
+
+func writeCSV(out io.Writer, entries []breaklog.BreakLogEntry) error {
+	w := csv.NewWriter(out)
+	err := w.Write([]string{"timestamp", "session", "kind", "phase", "interruption", "task", "project", "tags", "profile", "duration_minutes", "reason", "work_in_progress", "findings", "mood", "ended_early"})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		kind := e.Kind
+		if kind == "" {
+			kind = breaklog.ScribbleEntry
+		}
+		err = w.Write([]string{
+			e.Timestamp.Format(time.RFC3339),
+			e.Session,
+			string(kind),
+			string(e.Phase),
+			string(e.Interruption),
+			e.Task,
+			e.Project,
+			strings.Join(e.Tags, " "),
+			e.Profile,
+			fmt.Sprintf("%g", e.Duration.Minutes()),
+			e.Reason,
+			e.WorkInProgress,
+			e.Findings,
+			e.Mood,
+			strconv.FormatBool(e.EndedEarly),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func init() {
+	logCmd.AddCommand(exportCmd)
+
+	addFilterFlags(exportCmd)
+	exportCmd.Flags().StringP("format", "f", "json", "Output format, json or csv")
+	exportCmd.Flags().StringP("output", "o", "", "File to write to instead of stdout")
+}
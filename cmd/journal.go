@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/journal"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/spf13/cobra"
+)
+
+// journalCmd represents the log journal command
+var journalCmd = &cobra.Command{
+	Use:   "journal [YYYY-MM-DD]",
+	Short: "Write the day's sessions into a journal note",
+	Long: `Build a Markdown journal for a day, today unless a date is given, from the
+break log: a timeline of the sessions, the tasks worked on, scribbles,
+interruptions and totals. The journal is saved as the note "Journal <date>"
+and can be regenerated as often as needed, only the generated part of the
+note is replaced. Use --print to write it to stdout instead.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day := time.Now()
+		if len(args) == 1 {
+			var err error
+			day, err = time.ParseInLocation("2006-01-02", args[0], time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", args[0])
+			}
+		}
+		print, err := cmd.Flags().GetBool("print")
+		if err != nil {
+			return err
+		}
+		logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		if print {
+			fmt.Print(journal.Generate(day, logger.Entries()))
+			return nil
+		}
+		store, err := notes.NewStore(notes.DefaultDir)
+		if err != nil {
+			return err
+		}
+		name, err := journal.Update(store, day, logger.Entries())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Updated note %q\n", name)
+		return nil
+	},
+}
+
+func init() {
+	logCmd.AddCommand(journalCmd)
+
+	journalCmd.Flags().Bool("print", false, "Print the journal instead of saving it as a note")
+}
@@ -20,7 +20,9 @@ package cmd
 
 import (
 	"fmt"
-	// "github.com/SamD2021/boba-break/internal/breaklog"
+	"strings"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
 	"github.com/spf13/cobra"
 )
 
@@ -52,3 +54,25 @@ func init() {
 	// is called directly, e.g.:
 	// logCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
+
+// addFilterFlags adds the flags used to narrow down which log entries a
+// command looks at.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("task", "", "Only include entries for this task")
+	cmd.Flags().StringP("project", "p", "", "Only include entries for this project")
+	cmd.Flags().StringSlice("tag", nil, "Only include entries with these tags")
+	cmd.Flags().String("profile", "", "Only include entries recorded with this timer profile")
+}
+
+func filterFromFlags(cmd *cobra.Command) breaklog.Filter {
+	taskName, _ := cmd.Flags().GetString("task")
+	projectName, _ := cmd.Flags().GetString("project")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	profileName, _ := cmd.Flags().GetString("profile")
+	return breaklog.Filter{
+		Task:    taskName,
+		Project: projectName,
+		Profile: profileName,
+		Tags:    breaklog.ParseTags(strings.Join(tags, ",")),
+	}
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// logSearchCmd represents the log search command, kept for scripts written
+// before search could filter the log.
+var logSearchCmd = &cobra.Command{
+	Use:        "search [words]",
+	Short:      "Find log entries",
+	Deprecated: `use "boba-break search" with --project, --tag, --task or --profile instead`,
+	RunE:       runSearch,
+}
+
+func init() {
+	logCmd.AddCommand(logSearchCmd)
+
+	logSearchCmd.Flags().IntP("limit", "n", 20, "Show at most this many results, 0 for all")
+	addFilterFlags(logSearchCmd)
+}
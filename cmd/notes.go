@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+// notesCmd represents the notes command
+var notesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "Work with the notes written in the notes view",
+}
+
+var notesExportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Export notes and daily journals as a Markdown vault",
+	Long: `Write every note and a journal for every day in the break log to dir as
+Markdown files with YAML frontmatter (date, tags, task and session IDs), so
+the directory can be opened as an Obsidian vault or by similar tools. Each
+day's journal links to the notes last edited that day with [[wiki links]]
+and the notes link back. Journals go in a Journal folder.
+
+Exporting into the same directory again only rewrites files whose content
+changed, and removes the files of notes deleted or renamed since. Files
+the export didn't write are left alone.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := notes.NewStore(notes.DefaultDir)
+		if err != nil {
+			return err
+		}
+		logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		report, err := vault.Export(args[0], store, logger.Entries())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d files to %s, %d unchanged, %d removed\n", report.Written, args[0], report.Unchanged, report.Removed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notesCmd)
+	notesCmd.AddCommand(notesExportCmd)
+}
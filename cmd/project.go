@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/internal/project"
+	"github.com/spf13/cobra"
+)
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage the default project and tags of a directory",
+	Long: `Sessions started inside a directory, or any directory below it, are
+labelled with the project and tags set for it here unless they are given on
+the command line.
+
+A project can also commit them to a .boba-break.toml at its root:
+
+  project = "boba-break"
+  tags = ["go", "tui"]
+
+When both apply, the ones for the closer directory win.`,
+}
+
+var projectSetCmd = &cobra.Command{
+	Use:   "set <project>",
+	Short: "Set the default project and tags for a directory",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		if err := breaklog.ValidateTags(strings.Join(tags, ",")); err != nil {
+			return err
+		}
+		d := project.Defaults{
+			Dir:  dir,
+			Tags: breaklog.ParseTags(strings.Join(tags, ",")),
+		}
+		if len(args) > 0 {
+			d.Project = strings.TrimSpace(args[0])
+		}
+		if d.Project == "" && len(d.Tags) == 0 {
+			return fmt.Errorf("nothing to set, give a project or at least one --tag")
+		}
+		store, err := project.NewFileDefaultsStore(project.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		return store.Set(d)
+	},
+}
+
+var projectUnsetCmd = &cobra.Command{
+	Use:   "unset",
+	Short: "Remove the defaults set for a directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		store, err := project.NewFileDefaultsStore(project.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		return store.Unset(dir)
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every directory with defaults",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := project.NewFileDefaultsStore(project.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		current := project.ForCurrentDir()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "DIRECTORY\tPROJECT\tTAGS")
+		// Stored defaults win over the project file's for the same
+		// directory, only one of them is the current one.
+		currentStored := false
+		for _, d := range store.All() {
+			dir := d.Dir
+			if dir == current.Dir {
+				dir += " *"
+				currentStored = true
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", dir, d.Project, strings.Join(d.Tags, ", "))
+		}
+		if d := project.FileDefaults; d.Dir != "" {
+			path := filepath.Join(d.Dir, config.ProjectFileName)
+			if d.Dir == current.Dir && !currentStored {
+				path += " *"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", path, d.Project, strings.Join(d.Tags, ", "))
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectSetCmd)
+	projectCmd.AddCommand(projectUnsetCmd)
+	projectCmd.AddCommand(projectListCmd)
+
+	projectSetCmd.Flags().StringSlice("tag", nil, "Default tags for sessions")
+	projectSetCmd.Flags().StringP("dir", "d", ".", "Directory the defaults apply to")
+	projectUnsetCmd.Flags().StringP("dir", "d", ".", "Directory to remove the defaults of")
+}
@@ -20,11 +20,26 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/parkinglot"
+	"github.com/SamD2021/boba-break/internal/project"
+	"github.com/SamD2021/boba-break/internal/task"
 	"github.com/SamD2021/boba-break/tui"
 	"github.com/spf13/cobra"
 )
 
+var (
+	// cfgFile is the config file given with --config
+	cfgFile string
+	// cfg is the loaded configuration, set before any command runs
+	cfg = config.Default()
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "boba-break",
@@ -35,14 +50,84 @@ examples and usage of using your application. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The config commands have to work on a broken config file, they
+		// read it themselves.
+		if cmd == configCmd || cmd.Parent() == configCmd {
+			cmd.SilenceUsage = true
+			return nil
+		}
+		if err := loadConfig(); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		return nil
+	},
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	Run: func(cmd *cobra.Command, args []string) {
-		tui.Start()
+		path, err := configPath()
+		if err != nil {
+			panic(err)
+		}
+		tui.Start(cfg, path)
 		// breakmanagerui.Start()
+		clearScreen()
 	},
 }
 
+// configPath is the config file in use, from --config, $BOBA_BREAK_CONFIG or
+// the config directory.
+func configPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	return config.DefaultPath()
+}
+
+// loadConfig loads the config, lays the project file over it if there is
+// one, and points every store at the data directory.
+func loadConfig() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	c, p, err := config.LoadForDir(path, ".")
+	if err != nil {
+		return err
+	}
+	cfg = c
+	if p != nil {
+		project.FileDefaults = project.Defaults{
+			Dir:     p.Dir,
+			Project: strings.TrimSpace(p.Project),
+			Tags:    breaklog.ParseTags(strings.Join(p.Tags, ",")),
+		}
+	}
+	useDataDir(cfg)
+	return nil
+}
+
+func useDataDir(c config.Config) {
+	breaklog.DefaultFilePath = filepath.Join(c.DataDir, "entry.json")
+	task.DefaultFilePath = filepath.Join(c.DataDir, "tasks.json")
+	parkinglot.DefaultFilePath = filepath.Join(c.DataDir, "parkinglot.json")
+	project.DefaultFilePath = filepath.Join(c.DataDir, "projects.json")
+	notes.DefaultDir = filepath.Join(c.DataDir, "notes")
+	if c.NotesDir != "" {
+		notes.DefaultDir = c.NotesDir
+	}
+}
+
+// clearScreen wipes whatever the TUI left behind once it exits. Plain
+// subcommands don't call it so their output stays readable.
+func clearScreen() {
+	_, err := os.Stdout.Write([]byte{0x1B, 0x5B, 0x33, 0x3B, 0x4A, 0x1B, 0x5B, 0x48, 0x1B, 0x5B, 0x32, 0x4A})
+	if err != nil {
+		return
+	}
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -57,7 +142,7 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.boba-break.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $BOBA_BREAK_CONFIG or config.toml in the user config dir)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/search"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search [words]",
+	Short: "Search notes, scribbles and the break log",
+	Long: `Search every note and the text of every break log entry (work in progress,
+findings, reasons, tasks, projects, tags and mood) for entries containing all
+the given words, best matches first.
+
+--project, --tag, --task and --profile only search the log entries matching
+them, leaving out the notes. Without any words every such entry is listed.`,
+	RunE: runSearch,
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	filter := filterFromFlags(cmd)
+	if len(args) == 0 && filter.IsZero() {
+		return fmt.Errorf("nothing to search for, give some words or --project, --tag, --task or --profile")
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+	if err != nil {
+		return err
+	}
+	entries := filter.Apply(logger.Entries())
+	if len(args) == 0 {
+		for _, e := range entries {
+			printEntry(e)
+		}
+		return nil
+	}
+	var store *notes.Store
+	if filter.IsZero() {
+		if store, err = notes.NewStore(notes.DefaultDir); err != nil {
+			return err
+		}
+	}
+	ix, err := search.Build(store, entries)
+	if err != nil {
+		return err
+	}
+	results := ix.Search(strings.Join(args, " "))
+	if len(results) == 0 {
+		fmt.Println("No matches")
+		return nil
+	}
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SCORE\tKIND\tWHERE\tMATCH")
+	for _, r := range results {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Score, r.Kind, r.Title, r.Snippet)
+	}
+	return w.Flush()
+}
+
+func printEntry(e breaklog.BreakLogEntry) {
+	header := e.Timestamp.Format("2006-01-02 15:04")
+	switch e.Kind {
+	case breaklog.PhaseEntry:
+		header += fmt.Sprintf(" %s %v", e.Phase, e.Duration)
+	case breaklog.InterruptionEntry:
+		header += fmt.Sprintf(" %s interruption", e.Interruption)
+	default:
+		header += " scribble"
+	}
+	if e.Task != "" {
+		header += " [" + e.Task + "]"
+	}
+	if e.Project != "" {
+		header += " @" + e.Project
+	}
+	for _, t := range e.Tags {
+		header += " #" + t
+	}
+	fmt.Println(header)
+	for _, text := range []string{e.Reason, e.WorkInProgress, e.Findings, e.Mood} {
+		if text != "" {
+			fmt.Println("    " + strings.ReplaceAll(text, "\n", "\n    "))
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().IntP("limit", "n", 20, "Show at most this many results, 0 for all")
+	addFilterFlags(searchCmd)
+}
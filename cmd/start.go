@@ -4,9 +4,15 @@ Copyright © 2024 Samuel Dasilva
 package cmd
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/task"
 	"github.com/SamD2021/boba-break/tui/breakmanagerui"
+	"github.com/SamD2021/boba-break/tui/theme"
 	"github.com/spf13/cobra"
 )
 
@@ -31,18 +37,83 @@ to quickly create a Cobra application.`,
 			panic(err)
 		}
 		// fmt.Printf("Work-duration: %s\nBreak-duration: %s\n", workTime, breakTime)
-		workDuration, _ := time.ParseDuration(workTime)
-		if err != nil {
-			workDuration = time.Minute * 25
+		profileName := cfg.Profile
+		if cmd.Flags().Changed("profile") {
+			profileName, _ = cmd.Flags().GetString("profile")
 		}
-		breakDuration, _ := time.ParseDuration(breakTime)
-		if err != nil {
-			breakDuration = time.Minute * 5
+		run, err := cfg.WithProfile(profileName)
+		cobra.CheckErr(err)
+		timer := run.Timer
+		if cmd.Flags().Changed("work-duration") {
+			timer.Focus.Duration, err = time.ParseDuration(workTime)
+			cobra.CheckErr(err)
+		}
+		if cmd.Flags().Changed("break-duration") {
+			timer.Break.Duration, err = time.ParseDuration(breakTime)
+			cobra.CheckErr(err)
+		}
+		taskName, _ := cmd.Flags().GetString("task")
+		if taskName != "" {
+			create, _ := cmd.Flags().GetBool("new-task")
+			taskName, err = resolveTask(taskName, create)
+			cobra.CheckErr(err)
+		}
+		m := breakmanagerui.InitialModel(timer.Focus.Duration, timer.Break.Duration).
+			WithTheme(theme.Load(run.Theme)).
+			WithTask(taskName).
+			WithTimer(timer).
+			WithProfile(run.Profile).
+			WithNotifications(run.Notifications).
+			WithDisplay(run.Display).
+			WithBreaks(run.Breaks, run.BreakRoutine()).
+			WithKeys(run.KeysOf("break"))
+		if cmd.Flags().Changed("project") {
+			projectName, _ := cmd.Flags().GetString("project")
+			m = m.WithProject(projectName)
+		}
+		if cmd.Flags().Changed("tag") {
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			cobra.CheckErr(breaklog.ValidateTags(strings.Join(tags, ",")))
+			m = m.WithTags(breaklog.ParseTags(strings.Join(tags, ",")))
 		}
-		breakmanagerui.InitialModel(workDuration, breakDuration).Start()
+		m.Start()
+		clearScreen()
 	},
 }
 
+// resolveTask finds the task referred to by ID or name. Unless create is
+// set, a task that does not exist is an error suggesting the ones it may
+// have been a typo of.
+func resolveTask(ref string, create bool) (string, error) {
+	store, err := task.NewFileTaskStore(task.DefaultFilePath)
+	if err != nil {
+		return "", err
+	}
+	t, err := store.Find(ref)
+	if errors.Is(err, task.ErrNotFound) {
+		if !create {
+			return "", notFound(ref, store.Similar(ref))
+		}
+		t, err = store.Add(ref, 0)
+	}
+	if err != nil {
+		return "", err
+	}
+	return t.Name, nil
+}
+
+func notFound(ref string, similar []task.Task) error {
+	msg := fmt.Sprintf("no task %q, use --new-task to create it", ref)
+	if len(similar) > 0 {
+		names := make([]string, len(similar))
+		for i, t := range similar {
+			names[i] = fmt.Sprintf("%d %q", t.ID, t.Name)
+		}
+		msg += ", or did you mean " + strings.Join(names, ", ")
+	}
+	return errors.New(msg)
+}
+
 func init() {
 
 	// Here you will define your flags and configuration settings.
@@ -53,6 +124,11 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
-	startCmd.Flags().StringP("work-duration", "w", "25m", "Help message for duration")
-	startCmd.Flags().StringP("break-duration", "b", "5m", "Help message for duration")
+	startCmd.Flags().StringP("work-duration", "w", "25m", "Length of focus sessions (defaults to the profile's or timer.focus from the config)")
+	startCmd.Flags().StringP("break-duration", "b", "5m", "Length of breaks (defaults to the profile's or timer.break from the config)")
+	startCmd.Flags().String("profile", "", "Timer profile to use, like pomodoro, 52-17 or deep-work (defaults to profile from the config)")
+	startCmd.Flags().StringP("task", "t", "", "Task (ID or name) to record focus sessions against")
+	startCmd.Flags().Bool("new-task", false, "Create the --task if there is no task by that name")
+	startCmd.Flags().StringP("project", "p", "", "Project to label sessions with (defaults to the directory's project)")
+	startCmd.Flags().StringSlice("tag", nil, "Tags to label sessions with (defaults to the directory's tags)")
 }
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/stats"
+	"github.com/SamD2021/boba-break/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the log stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize focus and break time",
+	Long: `Summarize the phases recorded in the break log, showing total focus and
+break time and how much focus time went into each task compared with its
+estimate, as well as the interruptions logged each day.
+
+Use --by to group by project, tag or profile instead, and --project, --tag,
+--profile or --task to only count matching sessions.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		store, err := task.NewFileTaskStore(task.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		by, err := cmd.Flags().GetString("by")
+		if err != nil {
+			return err
+		}
+		entries := filterFromFlags(cmd).Apply(logger.Entries())
+		summary := stats.Summarize(entries, store.Tasks())
+
+		fmt.Printf("Focus: %d sessions, %v\n", summary.FocusSessions, summary.FocusTime)
+		fmt.Printf("Break: %d sessions, %v", summary.BreakSessions, summary.BreakTime)
+		if summary.EarlyBreaks > 0 {
+			fmt.Printf(", %d ended early", summary.EarlyBreaks)
+		}
+		fmt.Print("\n\n")
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		if days := stats.InterruptionsByDay(entries); len(days) > 0 {
+			fmt.Fprintln(w, "DAY\tINTERNAL\tEXTERNAL")
+			for _, d := range days {
+				fmt.Fprintf(w, "%s\t%d\t%d\n", d.Day.Format("2006-01-02"), d.Internal, d.External)
+			}
+			fmt.Fprintln(w)
+		}
+		switch by {
+		case "task":
+		case "project", "tag", "profile":
+			key := stats.ProjectKey
+			switch by {
+			case "tag":
+				key = stats.TagKey
+			case "profile":
+				key = stats.ProfileKey
+			}
+			fmt.Fprintf(w, "%s\tFOCUS\tSESSIONS\tBREAK\n", strings.ToUpper(by))
+			for _, g := range stats.GroupBy(entries, key) {
+				fmt.Fprintf(w, "%s\t%v\t%d\t%v\n", g.Key, g.FocusTime, g.FocusSessions, g.BreakTime)
+			}
+			return w.Flush()
+		default:
+			return fmt.Errorf("cannot group by %q, expected task, project, tag or profile", by)
+		}
+		fmt.Fprintln(w, "TASK\tTIME\tSESSIONS\tESTIMATE")
+		for _, ts := range summary.Tasks {
+			estimate := "-"
+			if ts.Estimate > 0 {
+				estimate = strconv.Itoa(ts.Estimate)
+			}
+			name := ts.Task
+			if ts.Done {
+				name += " (done)"
+			}
+			fmt.Fprintf(w, "%s\t%v\t%d\t%s\n", name, ts.FocusTime, ts.Sessions, estimate)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	logCmd.AddCommand(statsCmd)
+
+	addFilterFlags(statsCmd)
+	statsCmd.Flags().String("by", "task", "Group focus time by task, project, tag or profile")
+}
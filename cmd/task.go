@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SamD2021/boba-break/internal/task"
+	"github.com/spf13/cobra"
+)
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Manage the tasks focus sessions are recorded against",
+	Long: `Tasks give focus sessions a purpose. Create a task with an estimate of
+how many focus sessions it should take, pick it with "manage start --task"
+or the "t" key in the break manager, and mark it done when finished.`,
+}
+
+var taskAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a new task",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		estimate, err := cmd.Flags().GetInt("estimate")
+		if err != nil {
+			return err
+		}
+		store, err := task.NewFileTaskStore(task.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		t, err := store.Add(strings.Join(args, " "), estimate)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Added task %d: %s\n", t.ID, t.Name)
+		return nil
+	},
+}
+
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		store, err := task.NewFileTaskStore(task.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		tasks := store.Open()
+		if all {
+			tasks = store.Tasks()
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTASK\tESTIMATE\tSTATUS")
+		for _, t := range tasks {
+			status := "open"
+			if t.Done {
+				status = "done"
+			}
+			estimate := "-"
+			if t.Estimate > 0 {
+				estimate = strconv.Itoa(t.Estimate)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", t.ID, t.Name, estimate, status)
+		}
+		return w.Flush()
+	},
+}
+
+var taskDoneCmd = &cobra.Command{
+	Use:   "done <id|name>",
+	Short: "Mark a task as done",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := task.NewFileTaskStore(task.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		return store.Complete(strings.Join(args, " "))
+	},
+}
+
+var taskEstimateCmd = &cobra.Command{
+	Use:   "estimate <id|name> <sessions>",
+	Short: "Set how many focus sessions a task should take",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sessions, err := strconv.Atoi(args[len(args)-1])
+		if err != nil {
+			return fmt.Errorf("invalid number of sessions %q", args[len(args)-1])
+		}
+		store, err := task.NewFileTaskStore(task.DefaultFilePath)
+		if err != nil {
+			return err
+		}
+		return store.Estimate(strings.Join(args[:len(args)-1], " "), sessions)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+	taskCmd.AddCommand(taskAddCmd)
+	taskCmd.AddCommand(taskListCmd)
+	taskCmd.AddCommand(taskDoneCmd)
+	taskCmd.AddCommand(taskEstimateCmd)
+
+	taskAddCmd.Flags().IntP("estimate", "e", 0, "Estimated number of focus sessions")
+	taskListCmd.Flags().BoolP("all", "a", false, "Include tasks that are done")
+}
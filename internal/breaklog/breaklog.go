@@ -25,12 +25,52 @@ import (
 	"time"
 )
 
+// DefaultFilePath is where the break log is kept when nothing else is configured.
+var DefaultFilePath = "data/entry.json"
+
+// EntryKind tells scribbles apart from the entries recorded when a phase ends.
+// Entries written before kinds existed are scribbles.
+type EntryKind string
+
+const (
+	ScribbleEntry     EntryKind = "scribble"
+	PhaseEntry        EntryKind = "phase"
+	InterruptionEntry EntryKind = "interruption"
+)
+
+type Phase string
+
+const (
+	FocusPhase Phase = "focus"
+	BreakPhase Phase = "break"
+)
+
+// InterruptionType follows the Pomodoro split between interruptions that come
+// from yourself and ones that come from other people.
+type InterruptionType string
+
+const (
+	InternalInterruption InterruptionType = "internal"
+	ExternalInterruption InterruptionType = "external"
+)
+
 type BreakLogEntry struct {
-	Timestamp      time.Time     `json:"timestamp"`
-	Reason         string        `json:"reason,omitempty"` // Optional field
-	WorkInProgress string        `json:"work_in_progress"`
-	Findings       string        `json:"findings"`
-	Duration       time.Duration `json:"duration"`
+	Timestamp      time.Time        `json:"timestamp"`
+	Kind           EntryKind        `json:"kind,omitempty"`
+	Session        string           `json:"session,omitempty"`
+	Phase          Phase            `json:"phase,omitempty"`
+	Interruption   InterruptionType `json:"interruption,omitempty"`
+	Task           string           `json:"task,omitempty"`
+	Project        string           `json:"project,omitempty"`
+	Tags           []string         `json:"tags,omitempty"`
+	Profile        string           `json:"profile,omitempty"`
+	Reason         string           `json:"reason,omitempty"` // Optional field
+	WorkInProgress string           `json:"work_in_progress"`
+	Findings       string           `json:"findings"`
+	Mood           string           `json:"mood,omitempty"`
+	Duration       time.Duration    `json:"duration"`
+	// Whether a break was cut short, Duration is how long it lasted
+	EndedEarly bool `json:"ended_early,omitempty"`
 }
 
 type BreakLogger interface {
@@ -86,17 +126,78 @@ func NewFileBreakLogger(filepath string) (*FileBreakLogger, error) {
 func NewBreakLogEntry(finding string, Reason string) *BreakLogEntry {
 	return &BreakLogEntry{
 		Timestamp: time.Now(),
+		Kind:      ScribbleEntry,
 		Reason:    Reason,
 		Findings:  finding,
 	}
 }
 
+// NewPhaseEntry records a finished focus or break phase.
+func NewPhaseEntry(phase Phase, duration time.Duration) *BreakLogEntry {
+	return &BreakLogEntry{
+		Timestamp: time.Now(),
+		Kind:      PhaseEntry,
+		Phase:     phase,
+		Duration:  duration,
+	}
+}
+
+// NewInterruptionEntry records an interruption, with an optional note on what
+// caused it kept as the reason.
+func NewInterruptionEntry(kind InterruptionType, note string) *BreakLogEntry {
+	return &BreakLogEntry{
+		Timestamp:    time.Now(),
+		Kind:         InterruptionEntry,
+		Interruption: kind,
+		Reason:       note,
+	}
+}
+
+// NewSessionID identifies the focus session started at t, along with its
+// break and everything noted during either.
+func NewSessionID(t time.Time) string {
+	return t.Format("20060102-150405")
+}
+
+// IsPhase reports whether the entry marks the end of a phase rather than a scribble.
+func (e BreakLogEntry) IsPhase() bool {
+	return e.Kind == PhaseEntry
+}
+
+// Entries returns a copy of everything in the log, oldest first.
+func (f *FileBreakLogger) Entries() []BreakLogEntry {
+	return append([]BreakLogEntry(nil), f.entries...)
+}
+
 func (f *FileBreakLogger) SetFilePath(fp string) {
 	f.filepath = fp
 }
 
 func (f *FileBreakLogger) AddLogEntry(entry *BreakLogEntry) error {
-	file, err := os.OpenFile(f.filepath, os.O_CREATE|os.O_WRONLY, 0644)
+	f.entries = append(f.entries, *entry)
+	return f.save()
+}
+
+// UpdateLogEntry replaces the entry logged at the same time for the same
+// session with entry, for what is only known once it has been logged. An
+// entry that is not in the log is added.
+func (f *FileBreakLogger) UpdateLogEntry(entry *BreakLogEntry) error {
+	for i := len(f.entries) - 1; i >= 0; i-- {
+		e := f.entries[i]
+		if e.Timestamp.Equal(entry.Timestamp) && e.Session == entry.Session && e.Kind == entry.Kind {
+			f.entries[i] = *entry
+			return f.save()
+		}
+	}
+	return f.AddLogEntry(entry)
+}
+
+func (f *FileBreakLogger) save() error {
+	json_encoded, err := json.Marshal(f.entries)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.filepath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
 	}
@@ -106,13 +207,6 @@ func (f *FileBreakLogger) AddLogEntry(entry *BreakLogEntry) error {
 
 		}
 	}(file)
-	// Encode if necessary
-	// _, err := file.WriteString()
-	f.entries = append(f.entries, *entry)
-	json_encoded, err := json.Marshal(f.entries)
-	if err != nil {
-		return err
-	}
 	_, err = file.Write(json_encoded)
 	return err
 }
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breaklog
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseTags splits a comma or space separated list of tags, dropping
+// duplicates and empty items. Tags are case-insensitive and stored lowercase.
+func ParseTags(s string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		t = strings.ToLower(strings.TrimPrefix(t, "#"))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+// ValidateTags checks that a comma or space separated list only holds tags
+// made of letters, digits, '-', '_', '/' or '.'.
+func ValidateTags(s string) error {
+	for _, t := range ParseTags(s) {
+		for _, r := range t {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_/.", r) {
+				return fmt.Errorf("tag %q can't contain %q", t, r)
+			}
+		}
+	}
+	return nil
+}
+
+// HasTag reports whether the entry was labelled with tag.
+func (e BreakLogEntry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter narrows down log entries. Empty fields match everything and an entry
+// has to carry every tag listed to match.
+type Filter struct {
+	Task    string
+	Project string
+	Profile string
+	Tags    []string
+}
+
+// IsZero reports whether the filter lets every entry through.
+func (f Filter) IsZero() bool {
+	return f.Task == "" && f.Project == "" && f.Profile == "" && len(f.Tags) == 0
+}
+
+func (f Filter) Match(e BreakLogEntry) bool {
+	if f.Task != "" && !strings.EqualFold(f.Task, e.Task) {
+		return false
+	}
+	if f.Project != "" && !strings.EqualFold(f.Project, e.Project) {
+		return false
+	}
+	if f.Profile != "" && !strings.EqualFold(f.Profile, e.Profile) {
+		return false
+	}
+	for _, t := range f.Tags {
+		if !e.HasTag(t) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f Filter) Apply(entries []BreakLogEntry) []BreakLogEntry {
+	var matched []BreakLogEntry
+	for _, e := range entries {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breaklog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"work", []string{"work"}},
+		{"Work, #deep work", []string{"work", "deep"}},
+		{" a,,b  c ", []string{"a", "b", "c"}},
+		{"#", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{"", false},
+		{"client/acme v1.2 snake_case kebab-case", false},
+		{"café", false},
+		{"a+b", true},
+		{"ok, no!", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateTags(tt.in); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTags(%q) = %v, want error %v", tt.in, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	entry := BreakLogEntry{
+		Task:    "Write report",
+		Project: "Acme",
+		Profile: "deep",
+		Tags:    []string{"writing", "client"},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"zero", Filter{}, true},
+		{"task", Filter{Task: "write REPORT"}, true},
+		{"other task", Filter{Task: "Write"}, false},
+		{"project", Filter{Project: "acme"}, true},
+		{"other project", Filter{Project: "home"}, false},
+		{"profile", Filter{Profile: "Deep"}, true},
+		{"other profile", Filter{Profile: "short"}, false},
+		{"one tag", Filter{Tags: []string{"Client"}}, true},
+		{"every tag", Filter{Tags: []string{"client", "writing"}}, true},
+		{"missing tag", Filter{Tags: []string{"client", "urgent"}}, false},
+		{"all fields", Filter{Task: "write report", Project: "acme", Profile: "deep", Tags: []string{"writing"}}, true},
+		{"one field off", Filter{Task: "write report", Project: "home"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(entry); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	entries := []BreakLogEntry{
+		{Project: "acme", Findings: "one"},
+		{Project: "home", Findings: "two"},
+		{Project: "Acme", Findings: "three"},
+		{Findings: "four"},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		zero   bool
+		want   []string
+	}{
+		{"zero keeps everything", Filter{}, true, []string{"one", "two", "three", "four"}},
+		{"project", Filter{Project: "acme"}, false, []string{"one", "three"}},
+		{"nothing matches", Filter{Project: "work"}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range tt.filter.Apply(entries) {
+				got = append(got, e.Findings)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+			if zero := tt.filter.IsZero(); zero != tt.zero {
+				t.Errorf("IsZero() = %v, want %v", zero, tt.zero)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyList is the keys bound to an action, written as one key or a list of
+// them, like "s" or ["up", "k"].
+type KeyList []string
+
+func (k *KeyList) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		*k = KeyList{v}
+	case []interface{}:
+		keys := make(KeyList, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("keys are written as strings, got %v", item)
+			}
+			keys = append(keys, s)
+		}
+		*k = keys
+	default:
+		return fmt.Errorf("expected a key or a list of keys, got %v", v)
+	}
+	return nil
+}
+
+// action is something a view does on a key press. Actions in the same mode
+// are active at the same time and can't share a key.
+type action struct {
+	view  string
+	name  string
+	modes []string
+	keys  KeyList
+}
+
+// actions lists every action that can be bound, with its default keys, in
+// the order views list them in their help.
+var actions = []action{
+	{"break", "start", []string{"timer"}, KeyList{"s"}},
+	{"break", "reset", []string{"timer"}, KeyList{"r"}},
+	{"break", "quit", []string{"timer"}, KeyList{"q"}},
+	{"break", "back", []string{"timer"}, KeyList{"backspace"}},
+	{"break", "scribble", []string{"timer"}, KeyList{"n"}},
+	{"break", "task", []string{"timer"}, KeyList{"t"}},
+	{"break", "internal", []string{"timer"}, KeyList{"i"}},
+	{"break", "external", []string{"timer"}, KeyList{"e"}},
+	{"break", "park", []string{"timer"}, KeyList{"p"}},
+	{"break", "back_early", []string{"timer"}, KeyList{"b"}},
+	{"break", "next", []string{"timer"}, KeyList{"tab"}},
+	{"break", "history", []string{"timer"}, KeyList{"h"}},
+	{"break", "scroll_up", []string{"timer"}, KeyList{"up", "k"}},
+	{"break", "scroll_down", []string{"timer"}, KeyList{"down", "j"}},
+
+	{"notes", "back", []string{"editor", "list"}, KeyList{"ctrl+q"}},
+	{"notes", "list", []string{"editor"}, KeyList{"ctrl+o"}},
+	{"notes", "toggle", []string{"editor"}, KeyList{"ctrl+x"}},
+	{"notes", "promote", []string{"editor"}, KeyList{"ctrl+g"}},
+	{"notes", "preview", []string{"editor"}, KeyList{"ctrl+l"}},
+	{"notes", "history", []string{"editor"}, KeyList{"ctrl+r"}},
+	{"notes", "edit", []string{"editor"}, KeyList{"alt+e"}},
+	{"notes", "open", []string{"list"}, KeyList{"enter"}},
+	{"notes", "new", []string{"list"}, KeyList{"n"}},
+	{"notes", "journal", []string{"list"}, KeyList{"t"}},
+	{"notes", "rename", []string{"list"}, KeyList{"r"}},
+	{"notes", "delete", []string{"list"}, KeyList{"x"}},
+	{"notes", "deleted", []string{"list"}, KeyList{"R"}},
+	{"notes", "newer", []string{"history"}, KeyList{"up", "k"}},
+	{"notes", "older", []string{"history"}, KeyList{"down", "j"}},
+	{"notes", "restore", []string{"history"}, KeyList{"enter"}},
+	{"notes", "close_history", []string{"history"}, KeyList{"esc"}},
+
+	{"search", "up", []string{"search"}, KeyList{"up", "ctrl+p"}},
+	{"search", "down", []string{"search"}, KeyList{"down", "ctrl+n"}},
+	{"search", "open", []string{"search"}, KeyList{"enter"}},
+	{"search", "back", []string{"search"}, KeyList{"esc"}},
+
+	{"menu", "choose", []string{"menu"}, KeyList{"enter"}},
+	{"menu", "spinner", []string{"menu"}, KeyList{"s"}},
+	{"menu", "title", []string{"menu"}, KeyList{"T"}},
+	{"menu", "status", []string{"menu"}, KeyList{"S"}},
+	{"menu", "pagination", []string{"menu"}, KeyList{"P"}},
+	{"menu", "help", []string{"menu"}, KeyList{"H"}},
+}
+
+// typingModes take text, a single character bound there couldn't be typed.
+var typingModes = map[string]bool{
+	"notes.editor":  true,
+	"search.search": true,
+}
+
+// builtin is the keys the text input or list of a mode handles itself, by
+// what they do there.
+type builtin struct {
+	owner string
+	keys  map[string]string
+}
+
+// builtins are taken by the bubbles components under each mode, an action
+// bound to one of them would take it away.
+var builtins = map[string]builtin{
+	"notes.editor":  {"the editor", textareaKeys},
+	"notes.list":    {"the note list", listKeys},
+	"search.search": {"the search box", lineKeys},
+	"menu.menu":     {"the menu", listKeys},
+}
+
+// lineKeys edit a single line of text, in a text input or a text area.
+var lineKeys = map[string]string{
+	"right":         "move right",
+	"ctrl+f":        "move right",
+	"left":          "move left",
+	"ctrl+b":        "move left",
+	"alt+right":     "move a word right",
+	"alt+f":         "move a word right",
+	"alt+left":      "move a word left",
+	"alt+b":         "move a word left",
+	"alt+backspace": "delete the word before the cursor",
+	"ctrl+w":        "delete the word before the cursor",
+	"alt+delete":    "delete the word after the cursor",
+	"alt+d":         "delete the word after the cursor",
+	"ctrl+k":        "delete to the end of the line",
+	"ctrl+u":        "delete to the start of the line",
+	"backspace":     "delete the character before the cursor",
+	"ctrl+h":        "delete the character before the cursor",
+	"delete":        "delete the character under the cursor",
+	"ctrl+d":        "delete the character under the cursor",
+	"home":          "go to the start of the line",
+	"ctrl+a":        "go to the start of the line",
+	"end":           "go to the end of the line",
+	"ctrl+e":        "go to the end of the line",
+	"ctrl+v":        "paste",
+}
+
+// textareaKeys are the lineKeys along with moving between lines.
+var textareaKeys = merge(lineKeys, map[string]string{
+	"up":        "move up a line",
+	"ctrl+p":    "move up a line",
+	"down":      "move down a line",
+	"ctrl+n":    "move down a line",
+	"enter":     "start a new line",
+	"ctrl+m":    "start a new line",
+	"alt+<":     "go to the start",
+	"ctrl+home": "go to the start",
+	"alt+>":     "go to the end",
+	"ctrl+end":  "go to the end",
+	"alt+c":     "capitalize a word",
+	"alt+l":     "lowercase a word",
+	"alt+u":     "uppercase a word",
+	"ctrl+t":    "swap two characters",
+})
+
+// listKeys move around a list, outside of filtering.
+var listKeys = map[string]string{
+	"up":     "move up",
+	"k":      "move up",
+	"down":   "move down",
+	"j":      "move down",
+	"left":   "go to the previous page",
+	"h":      "go to the previous page",
+	"pgup":   "go to the previous page",
+	"b":      "go to the previous page",
+	"u":      "go to the previous page",
+	"right":  "go to the next page",
+	"l":      "go to the next page",
+	"pgdown": "go to the next page",
+	"f":      "go to the next page",
+	"d":      "go to the next page",
+	"home":   "go to the top",
+	"g":      "go to the top",
+	"end":    "go to the bottom",
+	"G":      "go to the bottom",
+	"/":      "filter",
+	"?":      "show the full help",
+}
+
+func merge(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// quitKey always quits, whatever the keys say.
+const quitKey = "ctrl+c"
+
+// KeysOf returns the keys of every action of view, the defaults with the
+// config's [keys.view] table laid over them.
+func (c Config) KeysOf(view string) map[string][]string {
+	keys := map[string][]string{}
+	for _, a := range actions {
+		if a.view != view {
+			continue
+		}
+		keys[a.name] = a.keys
+		if custom, ok := c.Keys[view][a.name]; ok {
+			keys[a.name] = normalize(custom)
+		}
+	}
+	return keys
+}
+
+// normalize names keys the way key presses are named, the space bar is
+// written "space" but pressed as " ".
+func normalize(keys KeyList) []string {
+	normal := make([]string, len(keys))
+	for i, k := range keys {
+		if k == "space" {
+			k = " "
+		}
+		normal[i] = k
+	}
+	return normal
+}
+
+// quote writes a key for an error message, as it is written in the file.
+func quote(k string) string {
+	if k == " " {
+		k = "space"
+	}
+	return fmt.Sprintf("%q", k)
+}
+
+func views() []string {
+	var views []string
+	seen := map[string]bool{}
+	for _, a := range actions {
+		if !seen[a.view] {
+			seen[a.view] = true
+			views = append(views, a.view)
+		}
+	}
+	return views
+}
+
+func actionNames(view string) []string {
+	var names []string
+	for _, a := range actions {
+		if a.view == view {
+			names = append(names, a.name)
+		}
+	}
+	return names
+}
+
+// validateKeys checks the [keys] tables only name known views and actions,
+// that no key is bound twice where both actions are active, and that none
+// takes a key the text input or list under it already handles.
+func validateKeys(cfg Config, bad func(key, msg string)) {
+	for _, view := range sortedKeys(cfg.Keys) {
+		known := actionNames(view)
+		if known == nil {
+			bad("keys."+view, fmt.Sprintf("unknown view, expected one of %s", strings.Join(views(), ", ")))
+			continue
+		}
+		for _, name := range sortedKeys(cfg.Keys[view]) {
+			if !contains(known, name) {
+				bad("keys."+view+"."+name, fmt.Sprintf("unknown action, expected one of %s", strings.Join(known, ", ")))
+			}
+		}
+	}
+
+	// Actions of each mode by the keys they are bound to
+	type binding struct{ name, key string }
+	bound := map[string]map[string]binding{}
+	for _, a := range actions {
+		keys, custom := cfg.Keys[a.view][a.name]
+		name := "keys." + a.view + "." + a.name
+		if !custom {
+			keys = a.keys
+		} else if len(keys) == 0 {
+			bad(name, "needs at least one key")
+			continue
+		}
+		for _, k := range normalize(keys) {
+			switch {
+			case strings.TrimSpace(k) == "" && k != " ":
+				bad(name, "keys cannot be empty")
+				continue
+			case k == quitKey:
+				bad(name, quitKey+" always quits and can't be bound")
+				continue
+			}
+			for _, mode := range a.modes {
+				mode = a.view + "." + mode
+				if typingModes[mode] && len([]rune(k)) == 1 {
+					bad(name, fmt.Sprintf("%s would be typed as text instead", quote(k)))
+				} else if b, ok := builtins[mode]; ok && b.keys[k] != "" {
+					bad(name, fmt.Sprintf("%s is what %s uses to %s", quote(k), b.owner, b.keys[k]))
+				}
+				if bound[mode] == nil {
+					bound[mode] = map[string]binding{}
+				}
+				other, ok := bound[mode][k]
+				if ok && other.name != a.name {
+					// Report it on the binding from the file, defaults
+					// don't conflict with each other.
+					if custom {
+						bad(name, fmt.Sprintf("%s is already bound to %s", quote(k), other.name))
+					} else {
+						bad(other.key, fmt.Sprintf("%s is already bound to %s", quote(k), a.name))
+					}
+					continue
+				}
+				bound[mode][k] = binding{a.name, name}
+			}
+		}
+	}
+}
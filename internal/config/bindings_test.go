@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys map[string]map[string]KeyList
+		want []string // key: message
+	}{
+		{"defaults", nil, nil},
+		{"free key", map[string]map[string]KeyList{"notes": {"back": {"alt+q"}}}, nil},
+		{"space", map[string]map[string]KeyList{"break": {"start": {"space"}}}, nil},
+		{
+			"unknown view",
+			map[string]map[string]KeyList{"timer": {"start": {"s"}}},
+			[]string{"keys.timer: unknown view, expected one of break, notes, search, menu"},
+		},
+		{
+			"no keys",
+			map[string]map[string]KeyList{"break": {"start": {}}},
+			[]string{"keys.break.start: needs at least one key"},
+		},
+		{
+			"quit key",
+			map[string]map[string]KeyList{"break": {"quit": {"ctrl+c"}}},
+			[]string{"keys.break.quit: ctrl+c always quits and can't be bound"},
+		},
+		{
+			"shared key",
+			map[string]map[string]KeyList{"break": {"start": {"r"}}},
+			[]string{`keys.break.start: "r" is already bound to reset`},
+		},
+		{
+			"typed as text",
+			map[string]map[string]KeyList{"search": {"back": {"q"}}},
+			[]string{`keys.search.back: "q" would be typed as text instead`},
+		},
+		{
+			"taken by the editor",
+			map[string]map[string]KeyList{"notes": {"preview": {"ctrl+e"}}},
+			[]string{`keys.notes.preview: "ctrl+e" is what the editor uses to go to the end of the line`},
+		},
+		{
+			"taken by the list",
+			map[string]map[string]KeyList{"notes": {"delete": {"d"}}},
+			[]string{`keys.notes.delete: "d" is what the note list uses to go to the next page`},
+		},
+		{
+			// Outside the editor the key is free
+			"taken in one mode only",
+			map[string]map[string]KeyList{"notes": {"back": {"ctrl+e"}}},
+			[]string{`keys.notes.back: "ctrl+e" is what the editor uses to go to the end of the line`},
+		},
+		{
+			"taken by the search box",
+			map[string]map[string]KeyList{"search": {"back": {"ctrl+u"}}},
+			[]string{`keys.search.back: "ctrl+u" is what the search box uses to delete to the start of the line`},
+		},
+		{
+			"taken by the menu",
+			map[string]map[string]KeyList{"menu": {"help": {"?"}}},
+			[]string{`keys.menu.help: "?" is what the menu uses to show the full help`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Keys = tt.keys
+			var got []string
+			validateKeys(cfg, func(key, msg string) { got = append(got, key+": "+msg) })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysOf(t *testing.T) {
+	cfg := Default()
+	cfg.Keys = map[string]map[string]KeyList{"break": {"start": {"space", "S"}}}
+	keys := cfg.KeysOf("break")
+	if got, want := keys["start"], []string{" ", "S"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("start = %q, want %q", got, want)
+	}
+	if got, want := keys["reset"], []string{"r"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reset = %q, want %q", got, want)
+	}
+}
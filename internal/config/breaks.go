@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Breaks is what the break screen offers to do during a break.
+type Breaks struct {
+	// FullScreen gives breaks a screen of their own instead of the timer.
+	FullScreen bool `toml:"full_screen"`
+	// Suggestions are shown one at a time, the next one every Rotate.
+	Suggestions []string `toml:"suggestions"`
+	Rotate      Duration `toml:"rotate"`
+	// Routine is the routine guided through at the start of every break,
+	// none if empty.
+	Routine string `toml:"routine"`
+}
+
+// Routine is a guided break, steps followed one after the other.
+type Routine struct {
+	Steps []Step `toml:"steps"`
+}
+
+// Step is one step of a routine. A step without a duration shares what the
+// others leave of the break.
+type Step struct {
+	Text     string   `toml:"text"`
+	Duration Duration `toml:"duration"`
+}
+
+func defaultSuggestions() []string {
+	return []string{
+		"Stand up and stretch",
+		"Drink a glass of water",
+		"Take a short walk",
+		"Look at something far away",
+		"Roll your shoulders and neck",
+		"Breathe slowly, in for 4 and out for 6",
+	}
+}
+
+// builtinRoutines are there without being in the config file. A routine of
+// the same name in the file replaces the built in one.
+func builtinRoutines() map[string]Routine {
+	step := func(text string, seconds int) Step {
+		return Step{Text: text, Duration: Duration{time.Duration(seconds) * time.Second}}
+	}
+	return map[string]Routine{
+		"eyes": {Steps: []Step{
+			step("Look at something at least 6 metres away", 20),
+			step("Close your eyes and let them rest", 20),
+			step("Blink slowly ten times", 15),
+			step("Roll your eyes in slow circles, both ways", 20),
+		}},
+		"stretch": {Steps: []Step{
+			step("Stand up and reach for the ceiling", 30),
+			step("Roll your shoulders backwards", 30),
+			step("Tilt your head to each side", 30),
+			step("Stretch your wrists and fingers", 30),
+			step("Reach for your toes, or get close", 30),
+		}},
+		"breathe": {Steps: []Step{
+			step("Breathe in for 4, hold for 4, out for 4, hold for 4", 120),
+			{Text: "Breathe normally and notice how you feel"},
+		}},
+	}
+}
+
+// RoutineNames lists the routines that can be picked, sorted.
+func (c Config) RoutineNames() []string {
+	names := make([]string, 0, len(c.Routines))
+	for name := range c.Routines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BreakRoutine returns the routine breaks are guided through, with no steps
+// if there is none.
+func (c Config) BreakRoutine() Routine {
+	return c.Routines[c.Breaks.Routine]
+}
+
+// Schedule says how long each step lasts in a break of length. Steps without
+// a duration share what the others leave, and steps adding up to more than
+// the break are shortened to fit it.
+func (r Routine) Schedule(length time.Duration) []time.Duration {
+	var timed time.Duration
+	untimed := 0
+	for _, s := range r.Steps {
+		if s.Duration.Duration > 0 {
+			timed += s.Duration.Duration
+		} else {
+			untimed++
+		}
+	}
+	scale := 1.0
+	if timed > length {
+		scale = float64(length) / float64(timed)
+	}
+	var share time.Duration
+	if untimed > 0 && timed < length {
+		share = (length - timed) / time.Duration(untimed)
+	}
+	lengths := make([]time.Duration, len(r.Steps))
+	for i, s := range r.Steps {
+		if s.Duration.Duration > 0 {
+			lengths[i] = time.Duration(float64(s.Duration.Duration) * scale)
+		} else {
+			lengths[i] = share
+		}
+	}
+	return lengths
+}
+
+// validateBreaks checks the suggestions, that the break routine exists and
+// that every routine has steps to follow.
+func validateBreaks(cfg Config, bad func(key, msg string)) {
+	for i, s := range cfg.Breaks.Suggestions {
+		if strings.TrimSpace(s) == "" {
+			bad("breaks.suggestions", fmt.Sprintf("suggestion %d is empty", i+1))
+		}
+	}
+	// No rotating keeps the one suggestion for the whole break
+	if cfg.Breaks.Rotate.Duration != 0 {
+		checkDuration("breaks.rotate", cfg.Breaks.Rotate, bad)
+	}
+	if cfg.Breaks.Routine != "" {
+		if _, ok := cfg.Routines[cfg.Breaks.Routine]; !ok {
+			bad("breaks.routine", fmt.Sprintf("unknown routine %q, expected one of %s", cfg.Breaks.Routine, strings.Join(cfg.RoutineNames(), ", ")))
+		}
+	}
+	for _, name := range cfg.RoutineNames() {
+		key := "routines." + name
+		steps := cfg.Routines[name].Steps
+		if len(steps) == 0 {
+			bad(key, "has no steps")
+		}
+		for i, s := range steps {
+			if strings.TrimSpace(s.Text) == "" {
+				bad(key, fmt.Sprintf("step %d has no text", i+1))
+			}
+			if s.Duration.Duration < 0 {
+				bad(key, fmt.Sprintf("step %d cannot last %v", i+1, s.Duration))
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoutineSchedule(t *testing.T) {
+	steps := func(seconds ...int) Routine {
+		var r Routine
+		for _, s := range seconds {
+			r.Steps = append(r.Steps, Step{Text: "step", Duration: Duration{time.Duration(s) * time.Second}})
+		}
+		return r
+	}
+	tests := []struct {
+		name    string
+		routine Routine
+		length  time.Duration
+		want    []time.Duration
+	}{
+		{"no steps", steps(), 5 * time.Minute, []time.Duration{}},
+		{"fits", steps(30, 60), 5 * time.Minute, []time.Duration{30 * time.Second, time.Minute}},
+		{"exactly the break", steps(60, 60), 2 * time.Minute, []time.Duration{time.Minute, time.Minute}},
+		{"shortened to fit", steps(60, 180), 2 * time.Minute, []time.Duration{30 * time.Second, 90 * time.Second}},
+		{"untimed share the rest", steps(60, 0, 0), 5 * time.Minute, []time.Duration{time.Minute, 2 * time.Minute, 2 * time.Minute}},
+		{"only untimed", steps(0, 0), time.Minute, []time.Duration{30 * time.Second, 30 * time.Second}},
+		{"nothing left to share", steps(60, 0), time.Minute, []time.Duration{time.Minute, 0}},
+		{"shortened leaves nothing", steps(120, 0), time.Minute, []time.Duration{time.Minute, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.routine.Schedule(tt.length); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Schedule(%v) = %v, want %v", tt.length, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinRoutinesFitDefaultBreak(t *testing.T) {
+	cfg := Default()
+	for _, name := range cfg.RoutineNames() {
+		var total time.Duration
+		for _, d := range cfg.Routines[name].Schedule(cfg.Timer.Break.Duration) {
+			total += d
+		}
+		if total > cfg.Timer.Break.Duration {
+			t.Errorf("routine %s lasts %v, longer than a %v break", name, total, cfg.Timer.Break)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// FileName is the name of the config file in the config directory.
+	FileName = "config.toml"
+	// PathEnv points at a config file to use instead of the default one.
+	PathEnv = "BOBA_BREAK_CONFIG"
+	// envPrefix starts the environment variables overriding single keys,
+	// e.g. BOBA_BREAK_TIMER_FOCUS for timer.focus.
+	envPrefix = "BOBA_BREAK_"
+)
+
+type Config struct {
+	// Where the break log, tasks, notes and the rest are kept. Relative
+	// paths are relative to the directory boba-break is started in.
+	DataDir string `toml:"data_dir"`
+	// Where notes are kept, notes in DataDir if empty.
+	NotesDir string `toml:"notes_dir"`
+	// Style of the views, one of Themes or a theme file in ThemesDir.
+	Theme string `toml:"theme"`
+	// Profile is the profile used unless another is picked, none if empty.
+	Profile       string             `toml:"profile"`
+	Timer         Timer              `toml:"timer"`
+	Notifications Notifications      `toml:"notifications"`
+	Display       Display            `toml:"display"`
+	Breaks        Breaks             `toml:"breaks"`
+	Profiles      map[string]Profile `toml:"profiles"`
+	// Routines are the guided breaks Breaks.Routine picks from.
+	Routines map[string]Routine `toml:"routines"`
+	// Keys rebinds the actions of each view, see KeysOf.
+	Keys map[string]map[string]KeyList `toml:"keys"`
+}
+
+// Themes are the built in themes. "auto" follows the terminal's background,
+// "colorblind" tells focus and break apart without red and green, and "mono"
+// is used whatever the theme when NO_COLOR is set.
+var Themes = []string{"auto", "dark", "light", "high-contrast", "colorblind", "mono"}
+
+type Timer struct {
+	Focus Duration `toml:"focus"`
+	Break Duration `toml:"break"`
+	// Every LongBreakEvery focus sessions the break lasts LongBreak
+	// instead, 0 turns long breaks off.
+	LongBreak      Duration `toml:"long_break"`
+	LongBreakEvery int      `toml:"long_break_every"`
+	// AutoStart starts the next phase as soon as one ends instead of
+	// waiting for a key press.
+	AutoStart bool `toml:"auto_start"`
+}
+
+type Notifications struct {
+	Enabled      bool   `toml:"enabled"`
+	FocusTitle   string `toml:"focus_title"`
+	FocusMessage string `toml:"focus_message"`
+	BreakTitle   string `toml:"break_title"`
+	BreakMessage string `toml:"break_message"`
+}
+
+// Display is how the break manager shows the time left.
+type Display struct {
+	// Clock is one of Clocks.
+	Clock string `toml:"clock"`
+	// Progress is one of Progresses.
+	Progress string `toml:"progress"`
+}
+
+// Clocks are the ways to show the time left: "big" digits readable from
+// across the room, or a "plain" line of text.
+var Clocks = []string{"big", "plain"}
+
+// Progresses are the ways to show how much of a session has gone by: a
+// "bar", a boba "cup" that fills up during focus and is drunk during breaks,
+// or "none".
+var Progresses = []string{"bar", "cup", "none"}
+
+// Default is the configuration used for anything the config file leaves out.
+func Default() Config {
+	return Config{
+		DataDir: "data",
+		Theme:   "auto",
+		Timer: Timer{
+			Focus:     Duration{25 * time.Minute},
+			Break:     Duration{5 * time.Minute},
+			LongBreak: Duration{15 * time.Minute},
+		},
+		Profiles: builtinProfiles(),
+		Routines: builtinRoutines(),
+		Notifications: Notifications{
+			Enabled:      true,
+			FocusTitle:   "Boba Time",
+			FocusMessage: "Time is up, Enjoy some Boba!",
+			BreakTitle:   "Get Working!",
+			BreakMessage: "Lets put the cup down and get busy!",
+		},
+		Display: Display{
+			Clock:    "big",
+			Progress: "bar",
+		},
+		Breaks: Breaks{
+			FullScreen:  true,
+			Suggestions: defaultSuggestions(),
+			Rotate:      Duration{time.Minute},
+		},
+	}
+}
+
+// Duration is a time.Duration written like "25m" or "1h30m" in the file.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected something like \"25m\" or \"1h30m\"", text)
+	}
+	d.Duration = v
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// String drops the zero units time.Duration leaves in, "25m" over "25m0s".
+func (d Duration) String() string {
+	s := d.Duration.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Dir returns boba-break's directory in the user's config directory.
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "boba-break"), nil
+}
+
+// DefaultPath returns the config file to use when none is given on the
+// command line: $BOBA_BREAK_CONFIG, or config.toml in Dir.
+func DefaultPath() (string, error) {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Error is a problem with one key of the config, Line is 0 when the value
+// did not come from the file.
+type Error struct {
+	Path string
+	Line int
+	Key  string
+	Msg  string
+}
+
+func (e Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s: %s", e.Path, e.Line, e.Key, e.Msg)
+	}
+	if e.Key != "" {
+		return fmt.Sprintf("%s: %s", e.Key, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// Load reads the config file at path over the defaults and applies the
+// environment overrides. A missing file is not an error. Every problem found
+// is returned, joined, each with the line it is on.
+func Load(path string) (Config, error) {
+	return load(path, nil)
+}
+
+// load reads the config file at path, lays the project file over it if
+// there is one, then applies the environment overrides.
+func load(path string, p *ProjectFile) (Config, error) {
+	cfg, src, unknown, err := read(path)
+	if err != nil {
+		return cfg, err
+	}
+	errs := unknown
+	if p != nil {
+		var perr error
+		cfg, perr = cfg.WithProject(*p)
+		errs = append(errs, perr)
+	}
+	errs = append(errs, applyEnv(&cfg))
+	errs = append(errs, unjoin(validate(cfg, path, src))...)
+	return cfg, errors.Join(errs...)
+}
+
+// LoadFile reads the config file at path like Load, leaving out the
+// environment overrides, for editing the file itself.
+func LoadFile(path string) (Config, error) {
+	cfg, src, unknown, err := read(path)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, errors.Join(append(unknown, unjoin(validate(cfg, path, src))...)...)
+}
+
+// Check tells whether value is valid for key, without changing c.
+func (c Config) Check(key, value string) error {
+	if err := c.Set(key, value); err != nil {
+		return err
+	}
+	for _, err := range unjoin(validate(c, "", nil)) {
+		var e Error
+		if errors.As(err, &e) && e.Key == key {
+			return errors.New(e.Msg)
+		}
+	}
+	return nil
+}
+
+// unjoin splits up errors put together with errors.Join.
+func unjoin(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// read decodes the config file at path over the defaults. Keys the config
+// doesn't have are returned apart, the file can still be used and checked.
+func read(path string) (Config, []byte, []error, error) {
+	cfg := Default()
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil, nil, nil
+	}
+	if err != nil {
+		return cfg, nil, nil, err
+	}
+	md, err := toml.Decode(string(src), &cfg)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			msg := perr.Message
+			if cause := errors.Unwrap(perr); msg == "" && cause != nil {
+				// Errors from a value's UnmarshalText are only kept as
+				// the cause.
+				msg = cause.Error()
+			}
+			return cfg, src, nil, Error{Path: path, Line: perr.Position.Line, Key: perr.LastKey, Msg: msg}
+		}
+		return cfg, src, nil, valueError(path, src, err)
+	}
+	var unknown []error
+	for _, k := range md.Undecoded() {
+		unknown = append(unknown, Error{Path: path, Line: keyLine(src, k.String()), Key: k.String(), Msg: "unknown key"})
+	}
+	return cfg, src, unknown, nil
+}
+
+// valueError finds the key behind err, an error from one of the values'
+// UnmarshalText which the decoder returns without saying where it was.
+func valueError(path string, src []byte, err error) error {
+	var raw map[string]interface{}
+	if _, derr := toml.Decode(string(src), &raw); derr == nil {
+		cfg := Default()
+		for _, key := range Keys() {
+			v, ok := lookup(raw, key)
+			if !ok {
+				continue
+			}
+			if serr := cfg.Set(key, fmt.Sprint(v)); serr != nil {
+				return Error{Path: path, Line: keyLine(src, key), Key: key, Msg: serr.Error()}
+			}
+		}
+		profiles, _ := raw["profiles"].(map[string]interface{})
+		for _, name := range sortedKeys(profiles) {
+			p, _ := profiles[name].(map[string]interface{})
+			for _, key := range []string{"focus", "break", "long_break"} {
+				v, ok := p[key]
+				if !ok {
+					continue
+				}
+				var d Duration
+				if derr := d.UnmarshalText([]byte(fmt.Sprint(v))); derr != nil {
+					key = "profiles." + name + "." + key
+					return Error{Path: path, Line: keyLine(src, key), Key: key, Msg: derr.Error()}
+				}
+			}
+		}
+		routines, _ := raw["routines"].(map[string]interface{})
+		for _, name := range sortedKeys(routines) {
+			r, _ := routines[name].(map[string]interface{})
+			// Steps are written inline or as an array of tables.
+			var steps []map[string]interface{}
+			switch s := r["steps"].(type) {
+			case []map[string]interface{}:
+				steps = s
+			case []interface{}:
+				for _, item := range s {
+					step, _ := item.(map[string]interface{})
+					steps = append(steps, step)
+				}
+			}
+			for _, step := range steps {
+				v, ok := step["duration"]
+				if !ok {
+					continue
+				}
+				var d Duration
+				if derr := d.UnmarshalText([]byte(fmt.Sprint(v))); derr != nil {
+					key := "routines." + name
+					return Error{Path: path, Line: keyLine(src, key), Key: key, Msg: derr.Error()}
+				}
+			}
+		}
+		views, _ := raw["keys"].(map[string]interface{})
+		for _, view := range sortedKeys(views) {
+			actions, _ := views[view].(map[string]interface{})
+			for _, name := range sortedKeys(actions) {
+				var keys KeyList
+				if kerr := keys.UnmarshalTOML(actions[name]); kerr != nil {
+					key := "keys." + view + "." + name
+					return Error{Path: path, Line: keyLine(src, key), Key: key, Msg: kerr.Error()}
+				}
+			}
+		}
+	}
+	return Error{Path: path, Msg: err.Error()}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookup finds a dotted key in a decoded table.
+func lookup(table map[string]interface{}, key string) (interface{}, bool) {
+	first, rest, nested := strings.Cut(key, ".")
+	v, ok := table[first]
+	if !ok || !nested {
+		return v, ok
+	}
+	sub, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(sub, rest)
+}
+
+// validate checks the values make sense. Each problem points at the line of
+// the key in src, or at the environment variable that set it.
+func validate(cfg Config, path string, src []byte) error {
+	var errs []error
+	bad := func(key, msg string) {
+		e := Error{Path: path, Key: key, Msg: msg}
+		if env := envName(key); os.Getenv(env) != "" {
+			e.Msg += " (set by " + env + ")"
+		} else if src != nil {
+			e.Line = keyLine(src, key)
+		}
+		errs = append(errs, e)
+	}
+	if strings.TrimSpace(cfg.DataDir) == "" {
+		bad("data_dir", "cannot be empty")
+	}
+	if !knownTheme(cfg.Theme) {
+		bad("theme", unknownTheme(cfg.Theme))
+	}
+	if !contains(Clocks, cfg.Display.Clock) {
+		bad("display.clock", fmt.Sprintf("unknown clock %q, expected one of %s", cfg.Display.Clock, strings.Join(Clocks, ", ")))
+	}
+	if !contains(Progresses, cfg.Display.Progress) {
+		bad("display.progress", fmt.Sprintf("unknown progress %q, expected one of %s", cfg.Display.Progress, strings.Join(Progresses, ", ")))
+	}
+	if cfg.Timer.LongBreakEvery < 0 {
+		bad("timer.long_break_every", "cannot be negative, use 0 to turn long breaks off")
+	}
+	for _, d := range []struct {
+		key   string
+		value Duration
+	}{
+		{"timer.focus", cfg.Timer.Focus},
+		{"timer.break", cfg.Timer.Break},
+		{"timer.long_break", cfg.Timer.LongBreak},
+	} {
+		checkDuration(d.key, d.value, bad)
+	}
+	validateProfiles(cfg, bad)
+	validateBreaks(cfg, bad)
+	validateKeys(cfg, bad)
+	// Theme files in use are checked along with the config.
+	themes := []string{cfg.Theme}
+	for _, name := range cfg.ProfileNames() {
+		if t := cfg.Profiles[name].Theme; t != nil && !contains(themes, *t) {
+			themes = append(themes, *t)
+		}
+	}
+	for _, name := range themes {
+		if !knownTheme(name) {
+			continue
+		}
+		if _, err := LoadTheme(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkDuration reports a phase length that is too short or too long.
+func checkDuration(key string, d Duration, bad func(key, msg string)) {
+	switch {
+	case d.Duration < time.Second:
+		bad(key, fmt.Sprintf("must be at least a second, got %v", d))
+	case d.Duration > 24*time.Hour:
+		bad(key, fmt.Sprintf("must be at most 24h, got %v", d))
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
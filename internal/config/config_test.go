@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyLine(t *testing.T) {
+	src := []byte(`# theme = "commented out"
+theme = "dark"
+"profile" = "deep-work"
+timer.break = "10m"
+
+[timer]
+  focus = "50m"
+long_break_every = 4 # every fourth
+
+[profiles.review]
+focus = "45m"
+
+[keys.break]
+start = "space"
+`)
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"theme", 2},
+		{"profile", 3},
+		{"timer.break", 4},
+		{"timer", 6},
+		{"timer.focus", 7},
+		{"timer.long_break_every", 8},
+		{"profiles.review", 10},
+		{"profiles.review.focus", 11},
+		{"keys.break.start", 14},
+		{"focus", 0},
+		{"data_dir", 0},
+		{"keys.break.quit", 0},
+	}
+	for _, tt := range tests {
+		if got := keyLine(src, tt.key); got != tt.want {
+			t.Errorf("keyLine(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func writeConfig(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if src != "" {
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string // No file if empty
+		env   map[string]string
+		focus time.Duration
+		// Errors expected, without their path, in the order they are found
+		want []Error
+	}{
+		{name: "no file", focus: 25 * time.Minute},
+		{name: "set", src: "[timer]\nfocus = \"50m\"\n", focus: 50 * time.Minute},
+		{
+			name:  "environment wins",
+			src:   "[timer]\nfocus = \"50m\"\n",
+			env:   map[string]string{"BOBA_BREAK_TIMER_FOCUS": "1h"},
+			focus: time.Hour,
+		},
+		{
+			name:  "every problem",
+			src:   "colour = \"red\"\n\n[timer]\nfocus = \"1ms\"\nlong_break_every = -1\n\n[display]\nclock = \"tiny\"\n",
+			focus: time.Millisecond,
+			want: []Error{
+				{Line: 1, Key: "colour", Msg: "unknown key"},
+				{Line: 8, Key: "display.clock", Msg: `unknown clock "tiny", expected one of big, plain`},
+				{Line: 5, Key: "timer.long_break_every", Msg: "cannot be negative, use 0 to turn long breaks off"},
+				{Line: 4, Key: "timer.focus", Msg: "must be at least a second, got 1ms"},
+			},
+		},
+		{
+			name:  "bad environment value",
+			env:   map[string]string{"BOBA_BREAK_TIMER_BREAK": "1ms"},
+			focus: 25 * time.Minute,
+			want:  []Error{{Key: "timer.break", Msg: "must be at least a second, got 1ms (set by BOBA_BREAK_TIMER_BREAK)"}},
+		},
+		{
+			name:  "bad duration",
+			src:   "[timer]\nbreak = \"soon\"\n",
+			focus: 25 * time.Minute,
+			want:  []Error{{Line: 2, Key: "timer.break", Msg: `invalid duration "soon", expected something like "25m" or "1h30m"`}},
+		},
+		{
+			name:  "unknown action",
+			src:   "[keys.break]\nfly = \"f\"\n",
+			focus: 25 * time.Minute,
+			want:  []Error{{Line: 2, Key: "keys.break.fly", Msg: "unknown action, expected one of " + strings.Join(actionNames("break"), ", ")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := Load(writeConfig(t, tt.src))
+			if cfg.Timer.Focus.Duration != tt.focus {
+				t.Errorf("timer.focus = %v, want %v", cfg.Timer.Focus, tt.focus)
+			}
+			var got []Error
+			for _, err := range unjoin(err) {
+				var e Error
+				if !errors.As(err, &e) {
+					t.Fatalf("Load() error %v isn't a config.Error", err)
+				}
+				e.Path = ""
+				got = append(got, e)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load() errors = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetInFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		key, value string
+		want       string
+	}{
+		{
+			name: "keeps the comment",
+			src:  "theme = \"dark\" # my theme\n",
+			key:  "theme", value: "light",
+			want: "theme = \"light\" # my theme\n",
+		},
+		{
+			name: "in a table",
+			src:  "[timer]\n  focus = \"25m\"\nbreak = \"5m\"\n",
+			key:  "timer.focus", value: "50m",
+			want: "[timer]\n  focus = \"50m\"\nbreak = \"5m\"\n",
+		},
+		{
+			name: "dotted",
+			src:  "timer.auto_start = false\n",
+			key:  "timer.auto_start", value: "true",
+			want: "timer.auto_start = true\n",
+		},
+		{
+			name: "added to its table",
+			src:  "[timer]\nbreak = \"5m\"\n\n[display]\nclock = \"big\"\n",
+			key:  "timer.focus", value: "50m",
+			want: "[timer]\nbreak = \"5m\"\nfocus = \"50m\"\n\n[display]\nclock = \"big\"\n",
+		},
+		{
+			name: "added before the tables",
+			src:  "data_dir = \"d\"\n[timer]\nfocus = \"25m\"\n",
+			key:  "theme", value: "light",
+			want: "data_dir = \"d\"\ntheme = \"light\"\n[timer]\nfocus = \"25m\"\n",
+		},
+		{
+			name: "new table",
+			src:  "theme = \"dark\"\n",
+			key:  "display.clock", value: "plain",
+			want: "theme = \"dark\"\n\n[display]\nclock = \"plain\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.src)
+			if err := SetInFile(path, tt.key, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("SetInFile() wrote %q, want %q", got, tt.want)
+			}
+			if _, err := LoadFile(path); err != nil {
+				t.Errorf("LoadFile() after SetInFile() = %v", err)
+			}
+		})
+	}
+}
+
+func TestSetInFileRefuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		key, value string
+	}{
+		{"invalid value", "[timer]\nfocus = \"25m\"\n", "timer.focus", "soon"},
+		{"out of range", "[timer]\nfocus = \"25m\"\n", "timer.focus", "1ms"},
+		{"unknown key", "theme = \"dark\"\n", "colour", "red"},
+		{"unknown key in the file", "colour = \"red\"\n", "theme", "light"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.src)
+			if err := SetInFile(path, tt.key, tt.value); err == nil {
+				t.Fatal("SetInFile() succeeded")
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.src {
+				t.Errorf("SetInFile() changed the file to %q", got)
+			}
+		})
+	}
+}
+
+func TestSetInFileCreates(t *testing.T) {
+	path := writeConfig(t, "")
+	if err := SetInFile(path, "timer.focus", "50m"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Timer.Focus.Duration != 50*time.Minute {
+		t.Errorf("timer.focus = %v, want 50m", cfg.Timer.Focus)
+	}
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Template is the config file written by "config init": every key with its
+// default value and what it does.
+func Template() string {
+	d := Default()
+	lit := func(key string) string {
+		s, _ := d.literal(key)
+		return s
+	}
+	var suggestions string
+	for _, s := range d.Breaks.Suggestions {
+		suggestions += "  " + strconv.Quote(s) + ",\n"
+	}
+	return fmt.Sprintf(`# Boba Break configuration. Every key is optional, anything left out uses
+# the default shown here. Keys can also be overridden with environment
+# variables named after them, e.g. BOBA_BREAK_TIMER_FOCUS=50m for timer.focus.
+# A .boba-break.toml in a project can set its project, tags, profile, notes_dir
+# and profiles on top of this file.
+
+# Where the break log, tasks, notes and the rest are kept. Relative paths are
+# relative to the directory boba-break is started in.
+data_dir = %s
+# Notes can be kept somewhere else, like a synced folder. Empty keeps them in
+# notes under data_dir.
+notes_dir = %s
+
+# auto follows the terminal's background. Also built in are dark, light,
+# high-contrast, colorblind and mono, or name a theme file in the themes
+# directory next to this file. NO_COLOR turns colours off whatever it says.
+theme = %s
+
+# Profile used unless "manage start --profile" or the main menu picks another.
+# Built in are pomodoro (25m/5m, 15m every 4th break), 52-17 and deep-work
+# (90m/20m). Leave it empty to use the timer below as it is.
+profile = %s
+
+[timer]
+# How long focus and break sessions last, like "25m" or "1h30m".
+focus = %s
+break = %s
+# Every long_break_every focus sessions take a long break instead, 0 turns
+# long breaks off.
+long_break = %s
+long_break_every = %s
+# Start the next session as soon as one ends instead of waiting for "s".
+auto_start = %s
+
+[notifications]
+# Desktop notifications sent when a session ends.
+enabled = %s
+focus_title = %s
+focus_message = %s
+break_title = %s
+break_message = %s
+
+[display]
+# How the break manager shows the time left: big digits or a plain line.
+clock = %s
+# How much of the session has gone by: a bar, a boba cup that fills up while
+# you focus and empties during breaks, or none.
+progress = %s
+
+[breaks]
+# Breaks get a screen of their own, with something to do during them.
+full_screen = %s
+# Shown one at a time, the next one every rotate, or each break if it is 0s.
+suggestions = [
+%s]
+rotate = %s
+# Guide every break through the steps of a routine before the suggestions:
+# eyes, stretch, breathe or one of your own. Empty for none.
+routine = %s
+
+# Profiles set any of focus, break, long_break, long_break_every, auto_start,
+# notifications and theme, the rest comes from above. A profile named like a
+# built in one replaces it.
+#
+# [profiles.review]
+# focus = "45m"
+# break = "10m"
+# notifications = false
+
+# Routines are steps followed one after the other. Steps without a duration
+# share what the others leave of the break, and steps longer than the break
+# are shortened to fit it. A routine named like a built in one replaces it.
+#
+# [routines.desk]
+# steps = [
+#   { text = "Stand up and shake out your arms", duration = "30s" },
+#   { text = "Refill your water" },
+# ]
+
+# Any view's keys can be rebound with a key or a list of keys, in
+# [keys.break], [keys.notes], [keys.search] and [keys.menu].
+#
+# [keys.break]
+# start = "space"
+# quit = ["ctrl+q", "Q"]
+`,
+		lit("data_dir"),
+		lit("notes_dir"),
+		lit("theme"),
+		lit("profile"),
+		lit("timer.focus"), lit("timer.break"),
+		lit("timer.long_break"), lit("timer.long_break_every"),
+		lit("timer.auto_start"),
+		lit("notifications.enabled"),
+		lit("notifications.focus_title"), lit("notifications.focus_message"),
+		lit("notifications.break_title"), lit("notifications.break_message"),
+		lit("display.clock"), lit("display.progress"),
+		lit("breaks.full_screen"), suggestions, lit("breaks.rotate"),
+		lit("breaks.routine"),
+	)
+}
+
+// Init writes the template to path, refusing to replace an existing file
+// unless force is set.
+func Init(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("%s already exists", path)
+	}
+	return write(path, []byte(Template()))
+}
+
+func write(path string, src []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, src, 0644)
+}
+
+// splitKey splits "timer.focus" into its table and name.
+func splitKey(key string) (table, name string) {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", key
+}
+
+// tableHeader returns the table a "[table]" line opens.
+func tableHeader(line string) (string, bool) {
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.Trim(line, "[]")), true
+}
+
+// assigns tells whether a line sets name.
+func assigns(line, name string) bool {
+	left, _, ok := strings.Cut(strings.TrimSpace(line), "=")
+	if !ok {
+		return false
+	}
+	left = strings.Trim(strings.TrimSpace(left), `"'`)
+	return left == name
+}
+
+// trailingComment returns the comment after the value on line, with the
+// space before it, or "" if there is none.
+func trailingComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			// Only basic strings have escapes.
+			escaped = c == '\\' && quote == '"'
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return " " + line[i:]
+		}
+	}
+	return ""
+}
+
+// keyLine finds the line setting key in src, counting from 1, or 0 if it is
+// not set there. It understands the tables and dotted keys config files are
+// written with, which is all line numbers in errors need.
+func keyLine(src []byte, key string) int {
+	table, name := splitKey(key)
+	current := ""
+	s := bufio.NewScanner(bytes.NewReader(src))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if t, ok := tableHeader(line); ok {
+			if t == key {
+				return n
+			}
+			current = t
+			continue
+		}
+		if current == table && assigns(line, name) {
+			return n
+		}
+		if current == "" && assigns(line, key) {
+			return n
+		}
+	}
+	return 0
+}
+
+// SetInFile changes one key of the config file at path, creating the file
+// from the template if needed. Only the line of the key changes, so comments
+// and layout are kept. Nothing is written if the value is invalid.
+func SetInFile(path, key, value string) error {
+	cfg, src, unknown, err := read(path)
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		return errors.Join(unknown...)
+	}
+	if src == nil {
+		src = []byte(Template())
+	}
+	if err := cfg.Set(key, value); err != nil {
+		return Error{Path: path, Key: key, Msg: err.Error()}
+	}
+	if err := validate(cfg, path, nil); err != nil {
+		return err
+	}
+	lit, err := cfg.literal(key)
+	if err != nil {
+		return err
+	}
+
+	table, name := splitKey(key)
+	lines := strings.Split(string(src), "\n")
+	if n := keyLine(src, key); n > 0 {
+		old := lines[n-1]
+		indent := old[:len(old)-len(strings.TrimLeft(old, " \t"))]
+		if table == "" || strings.Contains(strings.Split(old, "=")[0], ".") {
+			name = key
+		}
+		lines[n-1] = indent + name + " = " + lit + trailingComment(old)
+		return write(path, []byte(strings.Join(lines, "\n")))
+	}
+
+	// The key isn't in the file yet, add it to the end of its table.
+	insert := -1
+	current := ""
+	for i, l := range lines {
+		if t, ok := tableHeader(strings.TrimSpace(l)); ok {
+			if current == table && insert < 0 && table == "" {
+				insert = i
+			}
+			current = t
+			if t == table {
+				insert = i + 1
+			}
+			continue
+		}
+		if current == table && strings.TrimSpace(l) != "" && insert >= 0 {
+			insert = i + 1
+		}
+	}
+	switch {
+	case insert >= 0:
+		lines = append(lines[:insert], append([]string{name + " = " + lit}, lines[insert:]...)...)
+	case table == "":
+		lines = append([]string{name + " = " + lit}, lines...)
+	default:
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, "", "["+table+"]", name+" = "+lit, "")
+	}
+	return write(path, []byte(strings.Join(lines, "\n")))
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// field is a settable value of the config found by its dotted key.
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+// fields lists every key of v in the order they are declared.
+func fields(v reflect.Value, prefix string) []field {
+	var fs []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("toml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Map || fv.Kind() == reflect.Slice {
+			// Lists and tables of tables, like the profiles, are only
+			// written in the file.
+			continue
+		}
+		_, isText := fv.Addr().Interface().(encoding.TextUnmarshaler)
+		if fv.Kind() == reflect.Struct && !isText {
+			fs = append(fs, fields(fv, key+".")...)
+			continue
+		}
+		fs = append(fs, field{key: key, value: fv})
+	}
+	return fs
+}
+
+// Keys returns every key the config file can set, like "timer.focus".
+func Keys() []string {
+	cfg := Default()
+	var keys []string
+	for _, f := range fields(reflect.ValueOf(&cfg).Elem(), "") {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+func (c *Config) field(key string) (reflect.Value, error) {
+	for _, f := range fields(reflect.ValueOf(c).Elem(), "") {
+		if f.key == key {
+			return f.value, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown key %q, expected one of %s", key, strings.Join(Keys(), ", "))
+}
+
+// Get returns the value of key as it would be written on the command line.
+func (c Config) Get(key string) (string, error) {
+	v, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// Set parses value into key.
+func (c *Config) Set(key, value string) error {
+	v, err := c.field(key)
+	if err != nil {
+		return err
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q, expected true or false", value)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		v.SetInt(int64(n))
+	default:
+		return fmt.Errorf("%s cannot be set from the command line", key)
+	}
+	return nil
+}
+
+// literal writes the value of key as TOML.
+func (c Config) literal(key string) (string, error) {
+	v, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int:
+		return fmt.Sprint(v.Interface()), nil
+	}
+	s, err := c.Get(key)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Quote(s), nil
+}
+
+// envName is the environment variable overriding key.
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// applyEnv overrides keys with the environment variables set for them.
+func applyEnv(c *Config) error {
+	for _, key := range Keys() {
+		env := envName(key)
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			continue
+		}
+		if err := c.Set(key, value); err != nil {
+			return Error{Key: key, Msg: err.Error() + " (set by " + env + ")"}
+		}
+	}
+	return nil
+}
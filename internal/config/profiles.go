@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Profile is a named set of timer and notification settings to switch to.
+// Anything left out is taken from the rest of the config.
+type Profile struct {
+	Focus          *Duration `toml:"focus"`
+	Break          *Duration `toml:"break"`
+	LongBreak      *Duration `toml:"long_break"`
+	LongBreakEvery *int      `toml:"long_break_every"`
+	AutoStart      *bool     `toml:"auto_start"`
+	// Whether to send desktop notifications
+	Notifications *bool   `toml:"notifications"`
+	Theme         *string `toml:"theme"`
+}
+
+// builtinProfiles are there without being in the config file. A profile of
+// the same name in the file replaces the built in one.
+func builtinProfiles() map[string]Profile {
+	minutes := func(n int) *Duration {
+		return &Duration{time.Duration(n) * time.Minute}
+	}
+	every := func(n int) *int {
+		return &n
+	}
+	return map[string]Profile{
+		"pomodoro": {
+			Focus:          minutes(25),
+			Break:          minutes(5),
+			LongBreak:      minutes(15),
+			LongBreakEvery: every(4),
+		},
+		"52-17": {
+			Focus:          minutes(52),
+			Break:          minutes(17),
+			LongBreakEvery: every(0),
+		},
+		"deep-work": {
+			Focus:          minutes(90),
+			Break:          minutes(20),
+			LongBreakEvery: every(0),
+		},
+	}
+}
+
+// ProfileNames lists the profiles that can be picked, sorted.
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithProfile returns the config with the settings of the named profile in
+// place of the file's own. An empty name leaves it as it is.
+func (c Config) WithProfile(name string) (Config, error) {
+	if name == "" {
+		return c, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return c, fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	c.Profile = name
+	if p.Focus != nil {
+		c.Timer.Focus = *p.Focus
+	}
+	if p.Break != nil {
+		c.Timer.Break = *p.Break
+	}
+	if p.LongBreak != nil {
+		c.Timer.LongBreak = *p.LongBreak
+	}
+	if p.LongBreakEvery != nil {
+		c.Timer.LongBreakEvery = *p.LongBreakEvery
+	}
+	if p.AutoStart != nil {
+		c.Timer.AutoStart = *p.AutoStart
+	}
+	if p.Notifications != nil {
+		c.Notifications.Enabled = *p.Notifications
+	}
+	if p.Theme != nil {
+		c.Theme = *p.Theme
+	}
+	return c, nil
+}
+
+// Summary describes the timer in a few words, like "52m focus, 17m break".
+func (t Timer) Summary() string {
+	s := fmt.Sprintf("%v focus, %v break", t.Focus, t.Break)
+	if t.LongBreakEvery > 0 {
+		s += fmt.Sprintf(", %v long break every %d", t.LongBreak, t.LongBreakEvery)
+	}
+	return s
+}
+
+// validateProfiles checks the values of every profile and that the default
+// profile exists.
+func validateProfiles(cfg Config, bad func(key, msg string)) {
+	if cfg.Profile != "" {
+		if _, ok := cfg.Profiles[cfg.Profile]; !ok {
+			bad("profile", fmt.Sprintf("unknown profile %q, expected one of %s", cfg.Profile, strings.Join(cfg.ProfileNames(), ", ")))
+		}
+	}
+	for _, name := range cfg.ProfileNames() {
+		p := cfg.Profiles[name]
+		prefix := "profiles." + name + "."
+		for _, d := range []struct {
+			key   string
+			value *Duration
+		}{
+			{"focus", p.Focus},
+			{"break", p.Break},
+			{"long_break", p.LongBreak},
+		} {
+			if d.value != nil {
+				checkDuration(prefix+d.key, *d.value, bad)
+			}
+		}
+		if p.LongBreakEvery != nil && *p.LongBreakEvery < 0 {
+			bad(prefix+"long_break_every", "cannot be negative, use 0 to turn long breaks off")
+		}
+		if p.Theme != nil && !knownTheme(*p.Theme) {
+			bad(prefix+"theme", unknownTheme(*p.Theme))
+		}
+	}
+}
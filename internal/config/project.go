@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/SamD2021/boba-break/internal/breaklog"
+)
+
+// ProjectFileName is the file a project commits to share its settings. It
+// is looked for in the working directory and every directory above it.
+const ProjectFileName = ".boba-break.toml"
+
+// ProjectFile holds the settings of the project boba-break is started in.
+// They are laid over the user's config.
+type ProjectFile struct {
+	// Path of the file, Dir the directory it is in
+	Path string `toml:"-"`
+	Dir  string `toml:"-"`
+	// Default project and tags for sessions started in Dir or below it
+	Project string   `toml:"project"`
+	Tags    []string `toml:"tags"`
+	Profile string   `toml:"profile"`
+	// Where notes are kept, relative to Dir
+	NotesDir string `toml:"notes_dir"`
+	// Profiles shared by everyone working on the project
+	Profiles map[string]Profile `toml:"profiles"`
+
+	src []byte
+}
+
+// FindProject looks for the project file in dir and the directories above
+// it, returning its path if there is one.
+func FindProject(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// LoadProject reads the project file at path, reporting problems with the
+// line they are on like Load.
+func LoadProject(path string) (ProjectFile, error) {
+	p := ProjectFile{Path: path, Dir: filepath.Dir(path)}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	p.src = src
+	md, err := toml.Decode(string(src), &p)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return p, Error{Path: path, Line: perr.Position.Line, Key: perr.LastKey, Msg: perr.Message}
+		}
+		return p, valueError(path, src, err)
+	}
+	var errs []error
+	for _, k := range md.Undecoded() {
+		errs = append(errs, Error{Path: path, Line: keyLine(src, k.String()), Key: k.String(), Msg: "unknown key"})
+	}
+	bad := func(key, msg string) {
+		errs = append(errs, Error{Path: path, Line: keyLine(src, key), Key: key, Msg: msg})
+	}
+	for _, t := range p.Tags {
+		if err := breaklog.ValidateTags(t); err != nil {
+			bad("tags", err.Error())
+		}
+	}
+	validateProfiles(Config{Profiles: p.Profiles}, bad)
+	return p, errors.Join(errs...)
+}
+
+// LoadForDir loads the config at path like Load with the project file found
+// from dir laid over it, before the environment overrides so those still
+// win. The project file is nil if there is none.
+func LoadForDir(path, dir string) (Config, *ProjectFile, error) {
+	projectPath, ok := FindProject(dir)
+	if !ok {
+		cfg, err := Load(path)
+		return cfg, nil, err
+	}
+	p, err := LoadProject(projectPath)
+	if err != nil {
+		cfg, lerr := Load(path)
+		return cfg, nil, errors.Join(lerr, err)
+	}
+	cfg, err := load(path, &p)
+	return cfg, &p, err
+}
+
+// WithProject lays the project's settings over the config. The project's
+// profiles are added to the user's, replacing those of the same name.
+func (c Config) WithProject(p ProjectFile) (Config, error) {
+	if len(p.Profiles) > 0 {
+		profiles := make(map[string]Profile, len(c.Profiles)+len(p.Profiles))
+		for name, profile := range c.Profiles {
+			profiles[name] = profile
+		}
+		for name, profile := range p.Profiles {
+			profiles[name] = profile
+		}
+		c.Profiles = profiles
+	}
+	if p.NotesDir != "" {
+		c.NotesDir = p.NotesDir
+		if !filepath.IsAbs(c.NotesDir) {
+			c.NotesDir = filepath.Join(p.Dir, c.NotesDir)
+		}
+	}
+	if p.Profile != "" {
+		if _, ok := c.Profiles[p.Profile]; !ok {
+			return c, Error{
+				Path: p.Path,
+				Line: keyLine(p.src, "profile"),
+				Key:  "profile",
+				Msg:  fmt.Sprintf("unknown profile %q, expected one of %s", p.Profile, strings.Join(c.ProfileNames(), ", ")),
+			}
+		}
+		c.Profile = p.Profile
+	}
+	return c, nil
+}
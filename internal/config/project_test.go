@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadForDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		project string
+		env     map[string]string
+		profile string
+		wantErr string
+	}{
+		{name: "user config", config: "profile = \"pomodoro\"\n", profile: "pomodoro"},
+		{
+			name:    "project file over user config",
+			config:  "profile = \"pomodoro\"\n",
+			project: "profile = \"deep-work\"\n",
+			profile: "deep-work",
+		},
+		{
+			name:    "environment over project file",
+			config:  "profile = \"pomodoro\"\n",
+			project: "profile = \"deep-work\"\n",
+			env:     map[string]string{"BOBA_BREAK_PROFILE": "52-17"},
+			profile: "52-17",
+		},
+		{
+			name:    "unknown project profile",
+			project: "project = \"x\"\nprofile = \"nap\"\n",
+			wantErr: `:2: profile: unknown profile "nap"`,
+		},
+		{
+			name:    "bad project tag",
+			project: "project = \"x\"\n\ntags = [\"ok\", \"no!\"]\n",
+			wantErr: `:3: tags: tag "no!" can't contain '!'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := writeConfig(t, tt.config)
+			dir := t.TempDir()
+			if tt.project != "" {
+				if err := os.WriteFile(filepath.Join(dir, ProjectFileName), []byte(tt.project), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfg, _, err := LoadForDir(path, dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadForDir() = %v, want an error with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Profile != tt.profile {
+				t.Errorf("profile = %q, want %q", cfg.Profile, tt.profile)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ThemeColors are the colours a theme file can set, by what they are used
+// for.
+var ThemeColors = []string{
+	"primary",          // headers and borders
+	"accent",           // labels
+	"highlight",        // the time left
+	"selected",         // the item under the cursor
+	"focus",            // focus sessions
+	"break",            // breaks
+	"error",            // errors
+	"added",            // lines added to a note
+	"removed",          // lines removed from a note
+	"text",             // list items
+	"muted",            // secondary text
+	"subtle",           // help and separators
+	"title",            // text of title bars
+	"title_background", // title bars
+}
+
+// MarkdownStyles are the styles notes can be previewed in.
+var MarkdownStyles = []string{"dark", "light", "dracula", "pink", "notty", "ascii"}
+
+// ThemeFile is a custom theme, a built in theme with some of its colours
+// changed. It is kept in ThemesDir as <name>.toml:
+//
+//	base = "dark"
+//	markdown = "dark"
+//
+//	[colors]
+//	focus = "#56B4E9"
+//	break = { light = "#D55E00", dark = "#E69F00" }
+type ThemeFile struct {
+	Path string `toml:"-"`
+	// Built in theme the colours are laid over, "auto" if empty.
+	Base string `toml:"base"`
+	// Markdown style of the note preview, the base theme's if empty.
+	Markdown string                `toml:"markdown"`
+	Colors   map[string]ThemeColor `toml:"colors"`
+}
+
+// ThemeColor is a colour for light and for dark terminals, written once if
+// it is the same for both. Colours are "#rrggbb", "#rgb" or an ANSI colour
+// number.
+type ThemeColor struct {
+	Light string `toml:"light"`
+	Dark  string `toml:"dark"`
+}
+
+func (c *ThemeColor) UnmarshalTOML(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		c.Light, c.Dark = v, v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		c.Light, c.Dark = s, s
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			s := fmt.Sprint(v[k])
+			switch k {
+			case "light":
+				c.Light = s
+			case "dark":
+				c.Dark = s
+			default:
+				return fmt.Errorf("unknown key %q, a colour has light and dark", k)
+			}
+		}
+		if c.Light == "" || c.Dark == "" {
+			return errors.New("needs both light and dark")
+		}
+	default:
+		return fmt.Errorf("expected a colour, got %v", v)
+	}
+	return nil
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func checkColor(s string) error {
+	if hexColor.MatchString(s) {
+		return nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return nil
+	}
+	return fmt.Errorf("%q is not a colour, use #rrggbb, #rgb or an ANSI colour from 0 to 255", s)
+}
+
+// ThemesDir returns where custom themes are kept, next to the config file.
+func ThemesDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+func themePath(name string) (string, bool) {
+	dir, err := ThemesDir()
+	if err != nil || name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	path := filepath.Join(dir, name+".toml")
+	_, err = os.Stat(path)
+	return path, err == nil
+}
+
+// ThemeNames returns the built in themes followed by the custom ones.
+func ThemeNames() []string {
+	names := append([]string(nil), Themes...)
+	dir, err := ThemesDir()
+	if err != nil {
+		return names
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.toml"))
+	sort.Strings(paths)
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".toml")
+		if !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// knownTheme tells whether name is built in or has a theme file.
+func knownTheme(name string) bool {
+	if contains(Themes, name) {
+		return true
+	}
+	_, ok := themePath(name)
+	return ok
+}
+
+func unknownTheme(name string) string {
+	return fmt.Sprintf("unknown theme %q, expected one of %s or a file in the themes directory", name, strings.Join(ThemeNames(), ", "))
+}
+
+// LoadTheme reads the theme file of a custom theme, nil for a built in one.
+// A theme file named like a built in theme is never read.
+func LoadTheme(name string) (*ThemeFile, error) {
+	if contains(Themes, name) {
+		return nil, nil
+	}
+	path, ok := themePath(name)
+	if !ok {
+		return nil, errors.New(unknownTheme(name))
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := ThemeFile{Path: path}
+	md, err := toml.Decode(string(src), &t)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, Error{Path: path, Line: perr.Position.Line, Key: perr.LastKey, Msg: perr.Message}
+		}
+		// Find the colour UnmarshalTOML failed on.
+		var raw struct {
+			Colors map[string]interface{} `toml:"colors"`
+		}
+		if _, rerr := toml.Decode(string(src), &raw); rerr == nil {
+			for _, role := range sortedKeys(raw.Colors) {
+				var c ThemeColor
+				if cerr := c.UnmarshalTOML(raw.Colors[role]); cerr != nil {
+					key := "colors." + role
+					return nil, Error{Path: path, Line: keyLine(src, key), Key: key, Msg: cerr.Error()}
+				}
+			}
+		}
+		return nil, Error{Path: path, Msg: err.Error()}
+	}
+	var errs []error
+	bad := func(key, msg string) {
+		errs = append(errs, Error{Path: path, Line: keyLine(src, key), Key: key, Msg: msg})
+	}
+	for _, k := range md.Undecoded() {
+		// Colours given as tables are decoded by UnmarshalTOML.
+		if len(k) == 3 && k[0] == "colors" {
+			continue
+		}
+		bad(k.String(), "unknown key")
+	}
+	if t.Base == "" {
+		t.Base = "auto"
+	}
+	if !contains(Themes, t.Base) {
+		bad("base", fmt.Sprintf("unknown theme %q, a theme is based on one of %s", t.Base, strings.Join(Themes, ", ")))
+	}
+	if t.Markdown != "" && !contains(MarkdownStyles, t.Markdown) {
+		bad("markdown", fmt.Sprintf("unknown style %q, expected one of %s", t.Markdown, strings.Join(MarkdownStyles, ", ")))
+	}
+	for _, role := range sortedKeys(t.Colors) {
+		key := "colors." + role
+		if !contains(ThemeColors, role) {
+			bad(key, fmt.Sprintf("unknown colour, expected one of %s", strings.Join(ThemeColors, ", ")))
+			continue
+		}
+		c := t.Colors[role]
+		for _, s := range []string{c.Light, c.Dark} {
+			if err := checkColor(s); err != nil {
+				bad(key, err.Error())
+				break
+			}
+		}
+	}
+	return &t, errors.Join(errs...)
+}
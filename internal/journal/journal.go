@@ -0,0 +1,223 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package journal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/stats"
+)
+
+const (
+	namePrefix = "Journal "
+	dayLayout  = "2006-01-02"
+
+	// The generated part of a journal sits between these markers so it can
+	// be regenerated without touching anything written around it.
+	beginMarker = "<!-- boba-break:journal generated from the break log, edits in here are replaced -->"
+	endMarker   = "<!-- /boba-break:journal -->"
+)
+
+// Name returns the name of the journal note for day.
+func Name(day time.Time) string {
+	return namePrefix + day.Format(dayLayout)
+}
+
+// Day tells which day a journal note is for, if name is one.
+func Day(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, namePrefix) {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation(dayLayout, strings.TrimPrefix(name, namePrefix), time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day, true
+}
+
+func sameDay(t, day time.Time) bool {
+	t = t.Local()
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := day.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// Generate writes the journal of day from the break log as Markdown, wrapped
+// in the markers Merge looks for. The same entries always give the same text.
+func Generate(day time.Time, entries []breaklog.BreakLogEntry) string {
+	var today []breaklog.BreakLogEntry
+	for _, e := range entries {
+		if sameDay(e.Timestamp, day) {
+			today = append(today, e)
+		}
+	}
+	sort.SliceStable(today, func(i, j int) bool { return today[i].Timestamp.Before(today[j].Timestamp) })
+
+	var b strings.Builder
+	b.WriteString(beginMarker + "\n")
+	if len(today) == 0 {
+		b.WriteString("\nNothing recorded yet.\n\n")
+		b.WriteString(endMarker + "\n")
+		return b.String()
+	}
+
+	summary := stats.Summarize(today, nil)
+	internal, external := 0, 0
+	for _, d := range stats.InterruptionsByDay(today) {
+		internal += d.Internal
+		external += d.External
+	}
+	b.WriteString("\n## Totals\n\n")
+	fmt.Fprintf(&b, "- Focus: %d sessions, %v\n", summary.FocusSessions, summary.FocusTime)
+	fmt.Fprintf(&b, "- Break: %d sessions, %v\n", summary.BreakSessions, summary.BreakTime)
+	fmt.Fprintf(&b, "- Interruptions: %d internal, %d external\n", internal, external)
+
+	var timeline, scribbles, interruptions []string
+	for _, e := range today {
+		at := e.Timestamp.Local().Format("15:04")
+		switch e.Kind {
+		case breaklog.PhaseEntry:
+			start := e.Timestamp.Add(-e.Duration).Local().Format("15:04")
+			line := fmt.Sprintf("- %s–%s %s %v", start, at, e.Phase, e.Duration)
+			if e.EndedEarly {
+				line += ", back early"
+			}
+			if labels := labels(e); labels != "" {
+				line += " · " + labels
+			}
+			if e.WorkInProgress != "" {
+				line += "\n" + indent("Left off: "+e.WorkInProgress)
+			}
+			timeline = append(timeline, line)
+		case breaklog.InterruptionEntry:
+			line := fmt.Sprintf("- %s %s", at, e.Interruption)
+			if e.Reason != "" {
+				line += ": " + e.Reason
+			}
+			interruptions = append(interruptions, line)
+		default:
+			scribbles = append(scribbles, scribble(at, e))
+		}
+	}
+
+	section(&b, "Timeline", timeline)
+	var tasks []string
+	for _, ts := range summary.Tasks {
+		tasks = append(tasks, fmt.Sprintf("- %s: %d sessions, %v", ts.Task, ts.Sessions, ts.FocusTime))
+	}
+	section(&b, "Tasks", tasks)
+	section(&b, "Scribbles", scribbles)
+	section(&b, "Interruptions", interruptions)
+	b.WriteString("\n" + endMarker + "\n")
+	return b.String()
+}
+
+func section(b *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	for _, l := range lines {
+		b.WriteString(l + "\n")
+	}
+}
+
+// labels joins the task, project and tags of an entry.
+func labels(e breaklog.BreakLogEntry) string {
+	var parts []string
+	if e.Task != "" {
+		parts = append(parts, e.Task)
+	}
+	if e.Project != "" {
+		parts = append(parts, e.Project)
+	}
+	for _, t := range e.Tags {
+		parts = append(parts, "#"+t)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func scribble(at string, e breaklog.BreakLogEntry) string {
+	line := "- " + at
+	if labels := labels(e); labels != "" {
+		line += " · " + labels
+	}
+	if e.Mood != "" {
+		line += " · feeling " + e.Mood
+	}
+	for _, field := range []struct{ name, text string }{
+		{"In progress", e.WorkInProgress},
+		{"Findings", e.Findings},
+		{"Why", e.Reason},
+	} {
+		if field.text != "" {
+			line += "\n" + indent(field.name+": "+field.text)
+		}
+	}
+	return line
+}
+
+// indent nests text under a list item, keeping its own line breaks.
+func indent(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, l := range lines {
+		lines[i] = "  " + strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Merge puts a freshly generated journal in place of the one in existing,
+// leaving everything outside the markers alone. A note without markers gets
+// the journal added to the top.
+func Merge(existing, generated string) string {
+	begin := strings.Index(existing, beginMarker)
+	end := strings.Index(existing, endMarker)
+	if begin < 0 || end < begin {
+		if strings.TrimSpace(existing) == "" {
+			return generated
+		}
+		return generated + "\n" + existing
+	}
+	rest := existing[end+len(endMarker):]
+	rest = strings.TrimPrefix(rest, "\n")
+	return existing[:begin] + generated + rest
+}
+
+// Update regenerates the journal note of day in store and returns its name.
+func Update(store *notes.Store, day time.Time, entries []breaklog.BreakLogEntry) (string, error) {
+	name := Name(day)
+	generated := Generate(day, entries)
+	if !store.Exists(name) {
+		return name, store.Write(name, "# "+name+"\n\n"+generated)
+	}
+	existing, err := store.Read(name)
+	if err != nil {
+		return "", err
+	}
+	content := Merge(existing, generated)
+	if content == existing {
+		return name, nil
+	}
+	return name, store.Write(name, content)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package journal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+)
+
+func TestMerge(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	before := Generate(day, nil)
+	after := Generate(day, []breaklog.BreakLogEntry{
+		{Timestamp: at(9, 25), Kind: breaklog.PhaseEntry, Phase: breaklog.FocusPhase, Duration: 25 * time.Minute},
+		{Timestamp: at(9, 30), Kind: breaklog.ScribbleEntry, Findings: "found it"},
+	})
+
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{"empty note", "", after},
+		{"blank note", " \n\n", after},
+		{"no markers", "# Journal\n\nMy notes\n", after + "\n# Journal\n\nMy notes\n"},
+		{"only the journal", before, after},
+		{"text around", "# Journal\n\n" + before + "\nWritten after\n", "# Journal\n\n" + after + "\nWritten after\n"},
+		{"end marker first", endMarker + "\n" + beginMarker + "\n", after + "\n" + endMarker + "\n" + beginMarker + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Merge(tt.existing, after)
+			if got != tt.want {
+				t.Errorf("Merge() = %q, want %q", got, tt.want)
+			}
+			// Merging the same journal again changes nothing
+			if again := Merge(got, after); again != got {
+				t.Errorf("Merge() twice = %q, want %q", again, got)
+			}
+		})
+	}
+}
+
+func TestGenerateIsStable(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+	entries := []breaklog.BreakLogEntry{
+		{Timestamp: day.Add(10 * time.Hour), Kind: breaklog.ScribbleEntry, Findings: "second"},
+		{Timestamp: day.Add(9 * time.Hour), Kind: breaklog.ScribbleEntry, Findings: "first"},
+		{Timestamp: day.Add(-time.Hour), Kind: breaklog.ScribbleEntry, Findings: "the day before"},
+	}
+	got := Generate(day, entries)
+	if got != Generate(day, entries) {
+		t.Fatal("Generate() differs between runs")
+	}
+	if strings.Contains(got, "the day before") {
+		t.Errorf("Generate() kept an entry from another day:\n%s", got)
+	}
+	if first, second := strings.Index(got, "first"), strings.Index(got, "second"); first < 0 || second < first {
+		t.Errorf("Generate() doesn't list scribbles oldest first:\n%s", got)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// checkbox matches a Markdown task list item such as "- [ ] write tests",
+// capturing what comes before the mark, the mark and the item's text.
+var checkbox = regexp.MustCompile(`^(\s*[-*+] \[)([ xX])\](.*)$`)
+
+// ToggleCheckbox checks an unchecked item or unchecks a checked one. Lines
+// that aren't checklist items are returned unchanged.
+func ToggleCheckbox(line string) (string, bool) {
+	m := checkbox.FindStringSubmatch(line)
+	if m == nil {
+		return line, false
+	}
+	mark := "x"
+	if m[2] != " " {
+		mark = " "
+	}
+	return m[1] + mark + "]" + m[3], true
+}
+
+// Checklist counts the checked and total checklist items in content.
+func Checklist(content string) (done, total int) {
+	for _, line := range strings.Split(content, "\n") {
+		m := checkbox.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		total++
+		if m[2] != " " {
+			done++
+		}
+	}
+	return done, total
+}
+
+// Unchecked returns the text of every unchecked, non-empty checklist item.
+func Unchecked(content string) []string {
+	var items []string
+	for _, line := range strings.Split(content, "\n") {
+		m := checkbox.FindStringSubmatch(line)
+		if m == nil || m[2] != " " {
+			continue
+		}
+		if text := strings.TrimSpace(m[3]); text != "" {
+			items = append(items, text)
+		}
+	}
+	return items
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package notes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []DiffLine
+	}{
+		{"same", "a\nb", "a\nb", []DiffLine{{DiffSame, "a"}, {DiffSame, "b"}}},
+		{"added at the end", "a", "a\nb", []DiffLine{{DiffSame, "a"}, {DiffAdded, "b"}}},
+		{"removed at the start", "a\nb", "b", []DiffLine{{DiffRemoved, "a"}, {DiffSame, "b"}}},
+		{"changed", "a\nb\nc", "a\nx\nc", []DiffLine{
+			{DiffSame, "a"}, {DiffRemoved, "b"}, {DiffAdded, "x"}, {DiffSame, "c"},
+		}},
+		{"from empty", "", "a", []DiffLine{{DiffRemoved, ""}, {DiffAdded, "a"}}},
+		{"moved", "a\nb\nc", "c\na\nb", []DiffLine{
+			{DiffAdded, "c"}, {DiffSame, "a"}, {DiffSame, "b"}, {DiffRemoved, "c"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package notes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// historyDir holds the snapshots of each note in a directory per note.
+	historyDir = ".history"
+	// MaxSnapshots is how many snapshots are kept per note, older ones are
+	// dropped.
+	MaxSnapshots = 20
+	// SnapshotInterval keeps autosaving from filling the history with a
+	// snapshot per key press: a save only takes a snapshot when the last one
+	// is older than this.
+	SnapshotInterval = 5 * time.Minute
+
+	snapshotLayout = "20060102T150405.000000000"
+)
+
+// Snapshot is an earlier version of a note.
+type Snapshot struct {
+	Note string
+	Time time.Time
+	path string
+}
+
+func (s *Store) historyPath(name string) string {
+	return filepath.Join(s.dir, historyDir, strings.TrimSpace(name))
+}
+
+// History returns the snapshots of a note, newest first.
+func (s *Store) History(name string) ([]Snapshot, error) {
+	dir := s.historyPath(name)
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snaps []Snapshot
+	for _, f := range files {
+		t, err := time.ParseInLocation(snapshotLayout, strings.TrimSuffix(f.Name(), extension), time.Local)
+		if f.IsDir() || err != nil {
+			continue
+		}
+		snaps = append(snaps, Snapshot{
+			Note: strings.TrimSpace(name),
+			Time: t,
+			path: filepath.Join(dir, f.Name()),
+		})
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Time.After(snaps[j].Time) })
+	return snaps, nil
+}
+
+// Deleted returns the notes that were deleted but left a history behind,
+// most recently deleted first. ModTime is when the last snapshot was taken.
+func (s *Store) Deleted() ([]Note, error) {
+	dirs, err := os.ReadDir(filepath.Join(s.dir, historyDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var deleted []Note
+	for _, d := range dirs {
+		if !d.IsDir() || s.Exists(d.Name()) {
+			continue
+		}
+		snaps, err := s.History(d.Name())
+		if err != nil {
+			return nil, err
+		}
+		if len(snaps) == 0 {
+			continue
+		}
+		deleted = append(deleted, Note{
+			Name:    d.Name(),
+			Path:    s.path(d.Name()),
+			ModTime: snaps[0].Time,
+		})
+	}
+	sort.Slice(deleted, func(i, j int) bool { return deleted[i].ModTime.After(deleted[j].ModTime) })
+	return deleted, nil
+}
+
+// Undelete brings a deleted note back as it was when it was deleted.
+func (s *Store) Undelete(name string) error {
+	if s.Exists(name) {
+		return fmt.Errorf("%w: %q", ErrExists, name)
+	}
+	snaps, err := s.History(name)
+	if err != nil {
+		return err
+	}
+	if len(snaps) == 0 {
+		return fmt.Errorf("no history to bring %q back from", name)
+	}
+	return s.Restore(snaps[0])
+}
+
+func (s *Store) ReadSnapshot(snap Snapshot) (string, error) {
+	content, err := os.ReadFile(snap.path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Restore puts a snapshot back as the note's content. What the note held
+// before is kept as a snapshot so a restore can be undone.
+func (s *Store) Restore(snap Snapshot) error {
+	content, err := s.ReadSnapshot(snap)
+	if err != nil {
+		return err
+	}
+	if err := s.snapshot(snap.Note, true); err != nil {
+		return err
+	}
+	return s.write(snap.Note, content)
+}
+
+// snapshot keeps the note's current content in its history, unless force is
+// false and the last snapshot is recent.
+func (s *Store) snapshot(name string, force bool) error {
+	current, err := s.Read(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	snaps, err := s.History(name)
+	if err != nil {
+		return err
+	}
+	if len(snaps) > 0 {
+		if !force && time.Since(snaps[0].Time) < SnapshotInterval {
+			return nil
+		}
+		if last, err := s.ReadSnapshot(snaps[0]); err == nil && last == current {
+			return nil
+		}
+	}
+	if strings.TrimSpace(current) == "" {
+		return nil
+	}
+
+	dir := s.historyPath(name)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	file := filepath.Join(dir, time.Now().Format(snapshotLayout)+extension)
+	if err := os.WriteFile(file, []byte(current), 0644); err != nil {
+		return err
+	}
+	// Counting the one just taken, drop whatever is over the limit.
+	if len(snaps) >= MaxSnapshots {
+		for _, old := range snaps[MaxSnapshots-1:] {
+			os.Remove(old.path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package notes
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHistoryPruning(t *testing.T) {
+	tests := []struct {
+		saves int
+		want  int
+	}{
+		{1, 1},
+		{MaxSnapshots - 1, MaxSnapshots - 1},
+		{MaxSnapshots, MaxSnapshots},
+		{MaxSnapshots + 1, MaxSnapshots},
+		{MaxSnapshots + 5, MaxSnapshots},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.saves), func(t *testing.T) {
+			s, err := NewStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.saves; i++ {
+				if err := s.write("note", fmt.Sprint("version ", i)); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.snapshot("note", true); err != nil {
+					t.Fatal(err)
+				}
+			}
+			snaps, err := s.History("note")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(snaps) != tt.want {
+				t.Fatalf("kept %d snapshots, want %d", len(snaps), tt.want)
+			}
+			// The newest ones are kept
+			newest, err := s.ReadSnapshot(snaps[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			oldest, err := s.ReadSnapshot(snaps[len(snaps)-1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprint("version ", tt.saves-1); newest != want {
+				t.Errorf("newest snapshot = %q, want %q", newest, want)
+			}
+			if want := fmt.Sprint("version ", tt.saves-tt.want); oldest != want {
+				t.Errorf("oldest snapshot = %q, want %q", oldest, want)
+			}
+		})
+	}
+}
+
+func TestSnapshotSkips(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents []string
+		force    bool
+		want     int
+	}{
+		{"unchanged", []string{"a", "a", "a"}, true, 1},
+		{"blank", []string{" \n"}, true, 0},
+		{"within the interval", []string{"a", "b", "c"}, false, 1},
+		{"forced", []string{"a", "b", "c"}, true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range tt.contents {
+				if err := s.write("note", c); err != nil {
+					t.Fatal(err)
+				}
+				if err := s.snapshot("note", tt.force); err != nil {
+					t.Fatal(err)
+				}
+			}
+			snaps, err := s.History("note")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(snaps) != tt.want {
+				t.Errorf("kept %d snapshots, want %d", len(snaps), tt.want)
+			}
+		})
+	}
+}
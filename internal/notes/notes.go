@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir is where notes are kept, one Markdown file per note.
+var DefaultDir = "data/notes"
+
+const (
+	extension = ".md"
+	// lastFile remembers which note was open when the notes view was left.
+	lastFile = ".last"
+)
+
+var ErrExists = errors.New("a note with that name already exists")
+
+type Note struct {
+	Name    string
+	Path    string
+	ModTime time.Time
+}
+
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) (*Store, error) {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory the notes are stored in.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// ValidateName makes sure a note name can be used as a file name.
+func ValidateName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("note name cannot be empty")
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("note name %q can't contain slashes or start with a dot", name)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, strings.TrimSpace(name)+extension)
+}
+
+// List returns every note, most recently modified first.
+func (s *Store) List() ([]Note, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var notes []Note
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != extension {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, Note{
+			Name:    strings.TrimSuffix(f.Name(), extension),
+			Path:    filepath.Join(s.dir, f.Name()),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].ModTime.After(notes[j].ModTime) })
+	return notes, nil
+}
+
+func (s *Store) Exists(name string) bool {
+	_, err := os.Stat(s.path(name))
+	return err == nil
+}
+
+func (s *Store) Read(name string) (string, error) {
+	content, err := os.ReadFile(s.path(name))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// Write saves a note, creating it if needed. The content is written to a
+// temporary file first so a crash never leaves a half written note behind,
+// and what was there before goes into the note's history every so often.
+func (s *Store) Write(name string, content string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := s.snapshot(name, false); err != nil {
+		return err
+	}
+	return s.write(name, content)
+}
+
+func (s *Store) write(name string, content string) error {
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.WriteString(content)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(name))
+}
+
+// Append adds text to the end of a note on a line of its own, creating the
+// note if there is none by that name.
+func (s *Store) Append(name string, text string) error {
+	content, err := s.Read(name)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return s.Write(name, content+text+"\n")
+}
+
+// Create adds an empty note.
+func (s *Store) Create(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if s.Exists(name) {
+		return fmt.Errorf("%w: %q", ErrExists, name)
+	}
+	return s.Write(name, "")
+}
+
+func (s *Store) Rename(oldName string, newName string) error {
+	if err := ValidateName(newName); err != nil {
+		return err
+	}
+	if s.Exists(newName) {
+		return fmt.Errorf("%w: %q", ErrExists, newName)
+	}
+	err := os.Rename(s.path(oldName), s.path(newName))
+	if err != nil {
+		return err
+	}
+	// The history follows the note, unless an earlier note by the new name
+	// left one behind.
+	if _, err := os.Stat(s.historyPath(newName)); os.IsNotExist(err) {
+		err = os.Rename(s.historyPath(oldName), s.historyPath(newName))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if s.LastOpened() == strings.TrimSpace(oldName) {
+		return s.SetLastOpened(newName)
+	}
+	return nil
+}
+
+// Delete removes a note. Its history is kept, with the deleted content as
+// the newest snapshot, and comes back if a note by the same name is created.
+func (s *Store) Delete(name string) error {
+	if err := s.snapshot(name, true); err != nil {
+		return err
+	}
+	return os.Remove(s.path(name))
+}
+
+// LastOpened returns the note that was open last, or "" if it no longer exists.
+func (s *Store) LastOpened() string {
+	content, err := os.ReadFile(filepath.Join(s.dir, lastFile))
+	if err != nil {
+		return ""
+	}
+	name := strings.TrimSpace(string(content))
+	if name == "" || !s.Exists(name) {
+		return ""
+	}
+	return name
+}
+
+func (s *Store) SetLastOpened(name string) error {
+	return os.WriteFile(filepath.Join(s.dir, lastFile), []byte(strings.TrimSpace(name)), 0644)
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package notetemplate
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/config"
+)
+
+const extension = ".md"
+
+// builtin are the templates a new template directory starts out with. They
+// are written as files so they can be edited or removed like any other.
+var builtin = map[string]string{
+	"Standup": `# Standup {{date}}
+
+## Yesterday
+
+-
+
+## Today
+
+- {{task}}
+
+## Blockers
+
+-
+`,
+	"Retro": `# Retro {{date}}
+
+## What went well
+
+-
+
+## What didn't
+
+-
+
+## What to try next
+
+- [ ]
+`,
+	"Debugging log": `# Debugging {{task}}
+
+Started {{date}} {{time}}
+
+## Where I left off
+
+{{wip}}
+
+## Symptoms
+
+-
+
+## Hypotheses
+
+- [ ]
+
+## Findings
+
+-
+`,
+	"Meeting minutes": `# Meeting {{date}}
+
+Project: {{project}}
+
+## Attendees
+
+-
+
+## Notes
+
+-
+
+## Action items
+
+- [ ]
+`,
+}
+
+// Values fill in the placeholders of a template.
+type Values struct {
+	Time           time.Time
+	Task           string
+	Project        string
+	WorkInProgress string // From the last session
+}
+
+// Fill replaces the placeholders {{date}}, {{time}}, {{task}}, {{project}}
+// and {{wip}} in text.
+func Fill(text string, v Values) string {
+	return strings.NewReplacer(
+		"{{date}}", v.Time.Format("2006-01-02"),
+		"{{time}}", v.Time.Format("15:04"),
+		"{{task}}", v.Task,
+		"{{project}}", v.Project,
+		"{{wip}}", v.WorkInProgress,
+	).Replace(text)
+}
+
+// DefaultDir returns where templates are kept, next to the config file.
+func DefaultDir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+type Store struct {
+	dir string
+}
+
+// NewStore opens the templates in dir, creating it with the built in
+// templates the first time.
+func NewStore(dir string) (*Store, error) {
+	_, err := os.Stat(dir)
+	if err == nil {
+		return &Store{dir: dir}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	for name, text := range builtin {
+		err = os.WriteFile(filepath.Join(dir, name+extension), []byte(text), 0644)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory the templates are stored in.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Names returns the name of every template, sorted.
+func (s *Store) Names() ([]string, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != extension {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(f.Name(), extension))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Render reads a template and fills in its placeholders.
+func (s *Store) Render(name string, v Values) (string, error) {
+	text, err := os.ReadFile(filepath.Join(s.dir, name+extension))
+	if err != nil {
+		return "", err
+	}
+	return Fill(string(text), v), nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package parkinglot
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultFilePath is where parked thoughts wait until they are triaged.
+var DefaultFilePath = "data/parkinglot.json"
+
+// Item is a distracting thought captured during focus so it can be dealt
+// with on the next break.
+type Item struct {
+	ID        int       `json:"id"`
+	Text      string    `json:"text"`
+	Session   string    `json:"session,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FileParkingLot struct {
+	filepath string
+	items    []Item
+}
+
+func NewFileParkingLot(path string) (*FileParkingLot, error) {
+	items := []Item{}
+	file, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(file) != 0 {
+		err = json.Unmarshal(file, &items)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &FileParkingLot{
+		filepath: path,
+		items:    items,
+	}, nil
+}
+
+// Items returns the parked items, oldest first.
+func (p *FileParkingLot) Items() []Item {
+	return append([]Item(nil), p.items...)
+}
+
+func (p *FileParkingLot) Add(text string, session string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("nothing to park")
+	}
+	id := 1
+	for _, it := range p.items {
+		if it.ID >= id {
+			id = it.ID + 1
+		}
+	}
+	p.items = append(p.items, Item{
+		ID:        id,
+		Text:      text,
+		Session:   session,
+		CreatedAt: time.Now(),
+	})
+	return p.save()
+}
+
+// Remove takes an item out of the parking lot once it has been triaged.
+func (p *FileParkingLot) Remove(id int) error {
+	for i := range p.items {
+		if p.items[i].ID == id {
+			p.items = append(p.items[:i], p.items[i+1:]...)
+			return p.save()
+		}
+	}
+	return nil
+}
+
+func (p *FileParkingLot) save() error {
+	err := os.MkdirAll(filepath.Dir(p.filepath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	json_encoded, err := json.MarshalIndent(p.items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.filepath, json_encoded, 0644)
+}
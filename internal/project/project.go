@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultFilePath is where per-directory defaults are stored.
+var DefaultFilePath = "data/projects.json"
+
+// FileDefaults are the defaults from the project file found above the
+// working directory, if any. They are set when the config is loaded.
+var FileDefaults Defaults
+
+// Defaults are the project and tags sessions get when they are started
+// inside Dir or any directory below it.
+type Defaults struct {
+	Dir     string   `json:"dir"`
+	Project string   `json:"project,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+type FileDefaultsStore struct {
+	filepath string
+	defaults []Defaults
+}
+
+func NewFileDefaultsStore(path string) (*FileDefaultsStore, error) {
+	defaults := []Defaults{}
+	file, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(file) != 0 {
+		err = json.Unmarshal(file, &defaults)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &FileDefaultsStore{
+		filepath: path,
+		defaults: defaults,
+	}, nil
+}
+
+// All returns every directory that has defaults, sorted by path.
+func (s *FileDefaultsStore) All() []Defaults {
+	all := append([]Defaults(nil), s.defaults...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Dir < all[j].Dir })
+	return all
+}
+
+// Lookup finds the defaults of the closest directory containing dir.
+func (s *FileDefaultsStore) Lookup(dir string) (Defaults, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Defaults{}, false
+	}
+	var best Defaults
+	found := false
+	for _, d := range s.defaults {
+		if !within(dir, d.Dir) {
+			continue
+		}
+		if !found || len(d.Dir) > len(best.Dir) {
+			best = d
+			found = true
+		}
+	}
+	return best, found
+}
+
+// Set stores the defaults for d.Dir, replacing any that were there.
+func (s *FileDefaultsStore) Set(d Defaults) error {
+	dir, err := filepath.Abs(d.Dir)
+	if err != nil {
+		return err
+	}
+	d.Dir = dir
+	for i := range s.defaults {
+		if s.defaults[i].Dir == dir {
+			s.defaults[i] = d
+			return s.save()
+		}
+	}
+	s.defaults = append(s.defaults, d)
+	return s.save()
+}
+
+// Unset removes the defaults stored for exactly dir.
+func (s *FileDefaultsStore) Unset(dir string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	for i := range s.defaults {
+		if s.defaults[i].Dir == dir {
+			s.defaults = append(s.defaults[:i], s.defaults[i+1:]...)
+			return s.save()
+		}
+	}
+	return nil
+}
+
+func (s *FileDefaultsStore) save() error {
+	err := os.MkdirAll(filepath.Dir(s.filepath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+	json_encoded, err := json.MarshalIndent(s.defaults, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filepath, json_encoded, 0644)
+}
+
+func within(dir, parent string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// ForCurrentDir returns the defaults that apply to the working directory, or
+// empty defaults when none were stored. Between the stored defaults and the
+// project file's, those of the closer directory win, the stored ones if both
+// are for the same directory.
+func ForCurrentDir() Defaults {
+	store, err := NewFileDefaultsStore(DefaultFilePath)
+	if err != nil {
+		return FileDefaults
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return FileDefaults
+	}
+	d, ok := store.Lookup(wd)
+	if !ok || len(FileDefaults.Dir) > len(d.Dir) {
+		return FileDefaults
+	}
+	return d
+}
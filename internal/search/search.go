@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/journal"
+	"github.com/SamD2021/boba-break/internal/notes"
+)
+
+// snippetWidth is roughly how many characters of context a result shows.
+const snippetWidth = 72
+
+type Kind string
+
+const (
+	NoteDocument Kind = "note"
+	LogDocument  Kind = "log"
+)
+
+// Document is one searchable note or break log entry.
+type Document struct {
+	Kind  Kind
+	Title string // Note name, or what was logged and when
+	Text  string
+	Time  time.Time
+	// Note is where the document can be read: the note itself, or the
+	// journal of the day a log entry was written.
+	Note string
+}
+
+type Result struct {
+	Document
+	Score   int
+	Snippet string // Line of the first match, shortened around it
+}
+
+type Index struct {
+	docs []Document
+}
+
+// Build indexes every note in store and the text fields of every log entry.
+// Journal notes are left out, the log entries they are made of are indexed
+// already. A nil store leaves out the notes altogether.
+func Build(store *notes.Store, entries []breaklog.BreakLogEntry) (*Index, error) {
+	var ix Index
+	var ns []notes.Note
+	if store != nil {
+		var err error
+		if ns, err = store.List(); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range ns {
+		if _, ok := journal.Day(n.Name); ok {
+			continue
+		}
+		text, err := store.Read(n.Name)
+		if err != nil {
+			return nil, err
+		}
+		ix.docs = append(ix.docs, Document{
+			Kind:  NoteDocument,
+			Title: n.Name,
+			Text:  text,
+			Time:  n.ModTime,
+			Note:  n.Name,
+		})
+	}
+	for _, e := range entries {
+		text := entryText(e)
+		if text == "" {
+			continue
+		}
+		ix.docs = append(ix.docs, Document{
+			Kind:  LogDocument,
+			Title: entryTitle(e),
+			Text:  text,
+			Time:  e.Timestamp,
+			Note:  journal.Name(e.Timestamp.Local()),
+		})
+	}
+	return &ix, nil
+}
+
+func entryTitle(e breaklog.BreakLogEntry) string {
+	what := string(e.Kind)
+	switch {
+	case e.Kind == breaklog.PhaseEntry:
+		what = string(e.Phase)
+	case e.Kind == breaklog.InterruptionEntry:
+		what = string(e.Interruption) + " interruption"
+	case what == "":
+		what = string(breaklog.ScribbleEntry)
+	}
+	return fmt.Sprintf("%s %s", what, e.Timestamp.Local().Format("2006-01-02 15:04"))
+}
+
+// entryText puts the text fields of a log entry on a line each.
+func entryText(e breaklog.BreakLogEntry) string {
+	var lines []string
+	for _, s := range []string{e.WorkInProgress, e.Findings, e.Reason, e.Task, e.Project, e.Mood} {
+		if s = strings.TrimSpace(s); s != "" {
+			lines = append(lines, s)
+		}
+	}
+	if len(e.Tags) > 0 {
+		lines = append(lines, "#"+strings.Join(e.Tags, " #"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Search finds the documents containing every word of query, ignoring case.
+// Documents mentioning the words more often, in their title or as the whole
+// phrase rank higher, ties go to the most recent.
+func (ix *Index) Search(query string) []Result {
+	query = strings.ToLower(strings.TrimSpace(query))
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	var results []Result
+	for _, d := range ix.docs {
+		text := strings.ToLower(d.Text)
+		title := strings.ToLower(d.Title)
+		score := 0
+		for _, t := range terms {
+			n := strings.Count(text, t)
+			inTitle := strings.Contains(title, t)
+			if n == 0 && !inTitle {
+				score = 0
+				break
+			}
+			score += n
+			if inTitle {
+				score += 3
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		if len(terms) > 1 && strings.Contains(text, query) {
+			score += 5
+		}
+		results = append(results, Result{
+			Document: d,
+			Score:    score,
+			Snippet:  snippet(d.Text, terms),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Time.After(results[j].Time)
+	})
+	return results
+}
+
+// MatchLine returns the first line of text containing one of the words of
+// query, or -1.
+func MatchLine(text, query string) int {
+	terms := strings.Fields(strings.ToLower(query))
+	for i, line := range strings.Split(strings.ToLower(text), "\n") {
+		for _, t := range terms {
+			if strings.Contains(line, t) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// snippet cuts the first line matching terms down to the part around the
+// match.
+func snippet(text string, terms []string) string {
+	lines := strings.Split(text, "\n")
+	i := MatchLine(text, strings.Join(terms, " "))
+	if i < 0 {
+		return firstLine(lines)
+	}
+	line := []rune(strings.TrimSpace(lines[i]))
+	if len(line) <= snippetWidth {
+		return string(line)
+	}
+	at := max(index(line, terms), 0)
+	start := at - snippetWidth/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWidth
+	if end > len(line) {
+		end = len(line)
+		start = end - snippetWidth
+	}
+	s := string(line[start:end])
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(line) {
+		s += "…"
+	}
+	return s
+}
+
+// index finds the first of terms in line, ignoring case, and returns the rune
+// of line it starts at, or -1. Lowercasing can change how many bytes a
+// letter takes, like İ becoming i, so the match is counted in runes of a copy
+// lowered one rune for one rune, never as a byte offset into line.
+func index(line []rune, terms []string) int {
+	lower := make([]rune, len(line))
+	for i, r := range line {
+		lower[i] = unicode.ToLower(r)
+	}
+	s := string(lower)
+	for _, t := range terms {
+		if idx := strings.Index(s, t); idx >= 0 {
+			return utf8.RuneCountInString(s[:idx])
+		}
+	}
+	return -1
+}
+
+func firstLine(lines []string) string {
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	now := time.Now()
+	ix := &Index{docs: []Document{
+		{Title: "Ideas", Text: "coffee and tea\ncoffee again", Time: now.Add(-time.Hour)},
+		{Title: "Coffee shop", Text: "nothing here", Time: now.Add(-2 * time.Hour)},
+		{Title: "log", Text: "green tea", Time: now},
+	}}
+	type hit struct {
+		title string
+		score int
+	}
+	tests := []struct {
+		query string
+		want  []hit
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"latte", nil},
+		// Two mentions in the text lose to one in the title
+		{"coffee", []hit{{"Coffee shop", 3}, {"Ideas", 2}}},
+		{"  COFFEE ", []hit{{"Coffee shop", 3}, {"Ideas", 2}}},
+		// Ties go to the most recent
+		{"tea", []hit{{"log", 1}, {"Ideas", 1}}},
+		// Every word has to be there
+		{"coffee tea", []hit{{"Ideas", 3}}},
+		{"green coffee", nil},
+		// The whole phrase counts extra
+		{"and tea", []hit{{"Ideas", 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []hit
+			for _, r := range ix.Search(tt.query) {
+				got = append(got, hit{r.Title, r.Score})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchLine(t *testing.T) {
+	text := "first line\nSecond Line\nthird"
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"first", 0},
+		{"second", 1},
+		{"nothing third", 2},
+		{"line", 0},
+		{"fourth", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := MatchLine(text, tt.query); got != tt.want {
+			t.Errorf("MatchLine(%q) = %d, want %d", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	pad := func(n int) string { return strings.Repeat("é", n) }
+	runes := func(s string, from, to int) string { return string([]rune(s)[from:to]) }
+	start := "match" + pad(100)
+	middle := pad(40) + "match" + pad(60)
+	end := pad(100) + "match"
+	// İ takes two bytes and its lowercase one
+	dotted := strings.Repeat("İ", 40) + "Match" + strings.Repeat("İ", 60)
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{"short line", "first\n  the Match here  \nlast", []string{"match"}, "the Match here"},
+		{"no match", "\n\n  hello \nworld", []string{"zzz"}, "hello"},
+		{"empty", "", []string{"zzz"}, ""},
+		{"second term", "one\ntwo three", []string{"zzz", "three"}, "two three"},
+		{"long, match at the start", start, []string{"match"}, runes(start, 0, snippetWidth) + "…"},
+		{"long, match in the middle", middle, []string{"match"}, "…" + runes(middle, 16, 16+snippetWidth) + "…"},
+		{"long, match at the end", end, []string{"match"}, "…" + runes(end, 105-snippetWidth, 105)},
+		{"lowercasing changes the length", dotted, []string{"match"}, "…" + runes(dotted, 16, 16+snippetWidth) + "…"},
+		{"matching a changed letter", dotted, []string{strings.ToLower("İM")}, "…" + runes(dotted, 15, 15+snippetWidth) + "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet(tt.text, tt.terms); got != tt.want {
+				t.Errorf("snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
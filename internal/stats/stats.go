@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/task"
+)
+
+// Names used for time that wasn't tied to a task, project, tag or profile.
+const (
+	NoTask    = "(no task)"
+	NoProject = "(no project)"
+	NoTag     = "(untagged)"
+	NoProfile = "(no profile)"
+)
+
+type TaskStats struct {
+	Task      string
+	Sessions  int // Completed focus sessions
+	Estimate  int // Estimated focus sessions, 0 if never estimated
+	FocusTime time.Duration
+	Done      bool
+}
+
+type Summary struct {
+	FocusSessions int
+	BreakSessions int
+	FocusTime     time.Duration
+	BreakTime     time.Duration
+	// Breaks cut short by coming back early
+	EarlyBreaks int
+	Tasks       []TaskStats
+}
+
+// Summarize totals the phase entries of a break log and breaks the focus time
+// down per task. Tasks that have an estimate show up even before their first
+// session so estimated and actual sessions can be compared.
+func Summarize(entries []breaklog.BreakLogEntry, tasks []task.Task) Summary {
+	var s Summary
+	byTask := map[string]*TaskStats{}
+	get := func(name string) *TaskStats {
+		if name == "" {
+			name = NoTask
+		}
+		ts, ok := byTask[name]
+		if !ok {
+			ts = &TaskStats{Task: name}
+			byTask[name] = ts
+		}
+		return ts
+	}
+
+	for _, t := range tasks {
+		if t.Estimate == 0 && !t.Done {
+			continue
+		}
+		ts := get(t.Name)
+		ts.Estimate = t.Estimate
+		ts.Done = t.Done
+	}
+	for _, e := range entries {
+		if !e.IsPhase() {
+			continue
+		}
+		switch e.Phase {
+		case breaklog.FocusPhase:
+			s.FocusSessions++
+			s.FocusTime += e.Duration
+			ts := get(e.Task)
+			ts.Sessions++
+			ts.FocusTime += e.Duration
+		case breaklog.BreakPhase:
+			s.BreakSessions++
+			s.BreakTime += e.Duration
+			if e.EndedEarly {
+				s.EarlyBreaks++
+			}
+		}
+	}
+
+	for _, ts := range byTask {
+		s.Tasks = append(s.Tasks, *ts)
+	}
+	sort.Slice(s.Tasks, func(i, j int) bool {
+		if s.Tasks[i].FocusTime != s.Tasks[j].FocusTime {
+			return s.Tasks[i].FocusTime > s.Tasks[j].FocusTime
+		}
+		return s.Tasks[i].Task < s.Tasks[j].Task
+	})
+	return s
+}
+
+// Group is the time recorded under one project, tag or profile.
+type Group struct {
+	Key           string
+	FocusSessions int
+	FocusTime     time.Duration
+	BreakTime     time.Duration
+}
+
+// ProjectKey groups phases by project.
+func ProjectKey(e breaklog.BreakLogEntry) []string {
+	if e.Project == "" {
+		return []string{NoProject}
+	}
+	return []string{e.Project}
+}
+
+// ProfileKey groups phases by the timer profile they ran with.
+func ProfileKey(e breaklog.BreakLogEntry) []string {
+	if e.Profile == "" {
+		return []string{NoProfile}
+	}
+	return []string{e.Profile}
+}
+
+// TagKey groups phases by tag. A phase with several tags counts towards each.
+func TagKey(e breaklog.BreakLogEntry) []string {
+	if len(e.Tags) == 0 {
+		return []string{NoTag}
+	}
+	return e.Tags
+}
+
+// GroupBy totals the phase entries under the keys returned by key, largest
+// focus time first.
+func GroupBy(entries []breaklog.BreakLogEntry, key func(breaklog.BreakLogEntry) []string) []Group {
+	byKey := map[string]*Group{}
+	for _, e := range entries {
+		if !e.IsPhase() {
+			continue
+		}
+		for _, k := range key(e) {
+			g, ok := byKey[k]
+			if !ok {
+				g = &Group{Key: k}
+				byKey[k] = g
+			}
+			switch e.Phase {
+			case breaklog.FocusPhase:
+				g.FocusSessions++
+				g.FocusTime += e.Duration
+			case breaklog.BreakPhase:
+				g.BreakTime += e.Duration
+			}
+		}
+	}
+
+	groups := make([]Group, 0, len(byKey))
+	for _, g := range byKey {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].FocusTime != groups[j].FocusTime {
+			return groups[i].FocusTime > groups[j].FocusTime
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// DayInterruptions counts the interruptions logged on one day.
+type DayInterruptions struct {
+	Day      time.Time // Midnight local time
+	Internal int
+	External int
+}
+
+// InterruptionsByDay counts interruptions per calendar day, oldest first.
+func InterruptionsByDay(entries []breaklog.BreakLogEntry) []DayInterruptions {
+	byDay := map[time.Time]*DayInterruptions{}
+	for _, e := range entries {
+		if e.Kind != breaklog.InterruptionEntry {
+			continue
+		}
+		t := e.Timestamp.Local()
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		d, ok := byDay[day]
+		if !ok {
+			d = &DayInterruptions{Day: day}
+			byDay[day] = d
+		}
+		switch e.Interruption {
+		case breaklog.InternalInterruption:
+			d.Internal++
+		case breaklog.ExternalInterruption:
+			d.External++
+		}
+	}
+
+	days := make([]DayInterruptions, 0, len(byDay))
+	for _, d := range byDay {
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day.Before(days[j].Day) })
+	return days
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+)
+
+func focus(d time.Duration, project, profile string, tags ...string) breaklog.BreakLogEntry {
+	return breaklog.BreakLogEntry{
+		Kind:     breaklog.PhaseEntry,
+		Phase:    breaklog.FocusPhase,
+		Duration: d,
+		Project:  project,
+		Profile:  profile,
+		Tags:     tags,
+	}
+}
+
+func pause(d time.Duration, project, profile string, tags ...string) breaklog.BreakLogEntry {
+	e := focus(d, project, profile, tags...)
+	e.Phase = breaklog.BreakPhase
+	return e
+}
+
+func TestGroupBy(t *testing.T) {
+	entries := []breaklog.BreakLogEntry{
+		focus(25*time.Minute, "acme", "deep", "writing", "client"),
+		pause(5*time.Minute, "acme", "deep", "writing", "client"),
+		focus(50*time.Minute, "home", "", "writing"),
+		focus(25*time.Minute, "", "deep"),
+		pause(5*time.Minute, "", "deep"),
+		// Scribbles carry a project too but hold no time
+		{Kind: breaklog.ScribbleEntry, Project: "acme", Duration: time.Hour},
+	}
+	tests := []struct {
+		name string
+		key  func(breaklog.BreakLogEntry) []string
+		want []Group
+	}{
+		{"project", ProjectKey, []Group{
+			{Key: "home", FocusSessions: 1, FocusTime: 50 * time.Minute},
+			{Key: NoProject, FocusSessions: 1, FocusTime: 25 * time.Minute, BreakTime: 5 * time.Minute},
+			{Key: "acme", FocusSessions: 1, FocusTime: 25 * time.Minute, BreakTime: 5 * time.Minute},
+		}},
+		// Equal focus time falls back to the key
+		{"profile", ProfileKey, []Group{
+			{Key: NoProfile, FocusSessions: 1, FocusTime: 50 * time.Minute},
+			{Key: "deep", FocusSessions: 2, FocusTime: 50 * time.Minute, BreakTime: 10 * time.Minute},
+		}},
+		{"tag", TagKey, []Group{
+			{Key: "writing", FocusSessions: 2, FocusTime: 75 * time.Minute, BreakTime: 5 * time.Minute},
+			{Key: NoTag, FocusSessions: 1, FocusTime: 25 * time.Minute, BreakTime: 5 * time.Minute},
+			{Key: "client", FocusSessions: 1, FocusTime: 25 * time.Minute, BreakTime: 5 * time.Minute},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GroupBy(entries, tt.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupBy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupByEmpty(t *testing.T) {
+	if got := GroupBy(nil, ProjectKey); len(got) != 0 {
+		t.Errorf("GroupBy(nil) = %+v, want no groups", got)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFilePath is where tasks are stored, next to the break log.
+var DefaultFilePath = "data/tasks.json"
+
+var ErrNotFound = errors.New("task not found")
+
+type Task struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Estimate  int        `json:"estimate,omitempty"` // Estimated focus sessions
+	Done      bool       `json:"done"`
+	CreatedAt time.Time  `json:"created_at"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+}
+
+type FileTaskStore struct {
+	filepath string
+	tasks    []Task
+}
+
+func checkFile(filename string) error {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		err := os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		if err != nil {
+			return err
+		}
+		_, err = os.Create(filename)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewFileTaskStore(filepath string) (*FileTaskStore, error) {
+	tasks := []Task{}
+	err := checkFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	if len(file) != 0 {
+		err = json.Unmarshal(file, &tasks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &FileTaskStore{
+		filepath: filepath,
+		tasks:    tasks,
+	}, nil
+}
+
+// Tasks returns every task, finished or not, in creation order.
+func (s *FileTaskStore) Tasks() []Task {
+	return append([]Task(nil), s.tasks...)
+}
+
+// Open returns the tasks that have not been marked done.
+func (s *FileTaskStore) Open() []Task {
+	var open []Task
+	for _, t := range s.tasks {
+		if !t.Done {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// Find looks a task up by ID or, failing that, by case-insensitive name.
+func (s *FileTaskStore) Find(ref string) (*Task, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		for i := range s.tasks {
+			if s.tasks[i].ID == id {
+				return &s.tasks[i], nil
+			}
+		}
+	}
+	for i := range s.tasks {
+		if strings.EqualFold(s.tasks[i].Name, ref) {
+			return &s.tasks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrNotFound, ref)
+}
+
+// Similar returns the tasks whose name contains ref, or is a typo or two
+// away from it, for suggesting when Find comes up empty.
+func (s *FileTaskStore) Similar(ref string) []Task {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	var similar []Task
+	for _, t := range s.tasks {
+		name := strings.ToLower(t.Name)
+		if ref != "" && (strings.Contains(name, ref) || strings.Contains(ref, name) || distance(name, ref) <= 2) {
+			similar = append(similar, t)
+		}
+	}
+	return similar
+}
+
+// distance is the number of single letter edits between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func (s *FileTaskStore) Add(name string, estimate int) (*Task, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("task name cannot be empty")
+	}
+	if estimate < 0 {
+		return nil, errors.New("estimate cannot be negative")
+	}
+	if _, err := s.Find(name); err == nil {
+		return nil, fmt.Errorf("task %q already exists", name)
+	}
+	id := 1
+	for _, t := range s.tasks {
+		if t.ID >= id {
+			id = t.ID + 1
+		}
+	}
+	s.tasks = append(s.tasks, Task{
+		ID:        id,
+		Name:      name,
+		Estimate:  estimate,
+		CreatedAt: time.Now(),
+	})
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+	return &s.tasks[len(s.tasks)-1], nil
+}
+
+// Estimate changes the number of focus sessions a task is expected to take.
+func (s *FileTaskStore) Estimate(ref string, estimate int) error {
+	if estimate < 0 {
+		return errors.New("estimate cannot be negative")
+	}
+	t, err := s.Find(ref)
+	if err != nil {
+		return err
+	}
+	t.Estimate = estimate
+	return s.save()
+}
+
+func (s *FileTaskStore) Complete(ref string) error {
+	t, err := s.Find(ref)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	t.Done = true
+	t.DoneAt = &now
+	return s.save()
+}
+
+func (s *FileTaskStore) save() error {
+	json_encoded, err := json.MarshalIndent(s.tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filepath, json_encoded, 0644)
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package task
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"report", "report", 0},
+		{"report", "reprot", 2},
+		{"report", "reports", 1},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	s := &FileTaskStore{tasks: []Task{{Name: "Write report"}, {Name: "Review"}, {Name: "Email"}}}
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{"", nil},
+		{"report", []string{"Write report"}},
+		{"Write reprot", []string{"Write report"}},
+		{"review PR", []string{"Review"}},
+		{"emial", []string{"Email"}},
+		{"e", []string{"Write report", "Review", "Email"}},
+		{"lunch", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, task := range s.Similar(tt.ref) {
+			got = append(got, task.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Similar(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/journal"
+	"github.com/SamD2021/boba-break/internal/notes"
+)
+
+// JournalDir is the folder of the vault daily journals are written to.
+const JournalDir = "Journal"
+
+const dayLayout = "2006-01-02"
+
+// manifestName lists the files the last export wrote, relative to the vault,
+// so the next one can remove those it no longer writes. Files it didn't write
+// are never touched.
+const manifestName = ".boba-break-export"
+
+// Report counts what an export did.
+type Report struct {
+	Written   int
+	Unchanged int
+	// Files from the last export whose note was deleted or renamed since
+	Removed int
+}
+
+// frontmatter is the YAML block knowledge-base tools read metadata from.
+type frontmatter struct {
+	date     time.Time
+	tags     []string
+	task     string   // Notes: the task being worked on when last edited
+	tasks    []string // Journals: every task worked on that day
+	sessions []string
+}
+
+func (f frontmatter) String() string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "date: %s\n", f.date.Format(dayLayout))
+	list(&b, "tags", f.tags)
+	if f.task != "" {
+		fmt.Fprintf(&b, "task: %s\n", strconv.Quote(f.task))
+	}
+	list(&b, "tasks", f.tasks)
+	list(&b, "sessions", f.sessions)
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+func list(b *strings.Builder, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", key)
+	for _, v := range values {
+		fmt.Fprintf(b, "  - %s\n", strconv.Quote(v))
+	}
+}
+
+// day is everything recorded on one calendar day.
+type day struct {
+	date    time.Time
+	entries []breaklog.BreakLogEntry
+	notes   []string
+}
+
+func (d *day) frontmatter() frontmatter {
+	f := frontmatter{date: d.date, tags: []string{"journal"}}
+	seen := map[string]bool{}
+	add := func(kind, v string, to *[]string) {
+		if v != "" && !seen[kind+v] {
+			seen[kind+v] = true
+			*to = append(*to, v)
+		}
+	}
+	for _, e := range d.entries {
+		for _, t := range e.Tags {
+			add("tag", t, &f.tags)
+		}
+		add("task", e.Task, &f.tasks)
+		add("session", e.Session, &f.sessions)
+	}
+	return f
+}
+
+func midnight(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Export writes every note and a journal for every day in the break log to
+// dir as Markdown with frontmatter, linking each journal to the notes last
+// edited that day and back. Files that would come out the same are left
+// alone, so exporting into the same vault again only rewrites what changed.
+func Export(dir string, store *notes.Store, entries []breaklog.BreakLogEntry) (Report, error) {
+	var report Report
+	ns, err := store.List()
+	if err != nil {
+		return report, err
+	}
+	sorted := append([]breaklog.BreakLogEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	// Days are keyed by date rather than time.Time, equal instants with
+	// other locations would be different keys.
+	days := map[string]*day{}
+	getDay := func(t time.Time) *day {
+		date := midnight(t)
+		d, ok := days[date.Format(dayLayout)]
+		if !ok {
+			d = &day{date: date}
+			days[date.Format(dayLayout)] = d
+		}
+		return d
+	}
+	for _, e := range sorted {
+		d := getDay(e.Timestamp)
+		d.entries = append(d.entries, e)
+	}
+
+	produced := map[string]bool{}
+	write := func(rel, content string) error {
+		produced[rel] = true
+		path := filepath.Join(dir, rel)
+		existing, err := os.ReadFile(path)
+		if err == nil && bytes.Equal(existing, []byte(content)) {
+			report.Unchanged++
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+		report.Written++
+		return nil
+	}
+
+	// Journal notes are exported with the days below so they carry the
+	// latest log and their links.
+	journals := map[string]string{}
+	for _, n := range ns {
+		content, err := store.Read(n.Name)
+		if err != nil {
+			return report, err
+		}
+		if date, ok := journal.Day(n.Name); ok {
+			journals[date.Format(dayLayout)] = content
+			getDay(date)
+			continue
+		}
+		d := getDay(n.ModTime)
+		d.notes = append(d.notes, n.Name)
+
+		f := frontmatter{date: d.date, tags: []string{"note"}}
+		if e, ok := sessionAt(d.entries, n.ModTime); ok {
+			f.tags = append(f.tags, e.Tags...)
+			f.task = e.Task
+			if e.Session != "" {
+				f.sessions = []string{e.Session}
+			}
+		}
+		body := strings.TrimRight(content, "\n") +
+			fmt.Sprintf("\n\n---\nWritten on [[%s]]\n", journal.Name(d.date))
+		if err := write(n.Name+".md", f.String()+body); err != nil {
+			return report, err
+		}
+	}
+
+	for key, d := range days {
+		name := journal.Name(d.date)
+		content, ok := journals[key]
+		if !ok {
+			content = "# " + name + "\n\n"
+		}
+		content = journal.Merge(content, journal.Generate(d.date, d.entries))
+		content = strings.TrimRight(content, "\n") + "\n"
+		if len(d.notes) > 0 {
+			sort.Strings(d.notes)
+			content += "\n## Notes\n\n"
+			for _, n := range d.notes {
+				content += "- [[" + n + "]]\n"
+			}
+		}
+		if err := write(filepath.Join(JournalDir, name+".md"), d.frontmatter().String()+content); err != nil {
+			return report, err
+		}
+	}
+
+	removed, err := prune(dir, produced)
+	report.Removed = removed
+	return report, err
+}
+
+// prune removes the files the last export wrote that this one didn't, and
+// records what this one wrote for the next.
+func prune(dir string, produced map[string]bool) (int, error) {
+	path := filepath.Join(dir, manifestName)
+	last, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	removed := 0
+	for _, rel := range strings.Split(string(last), "\n") {
+		// The manifest sits in the vault, don't trust it to stay inside
+		if !filepath.IsLocal(rel) || produced[rel] {
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, rel))
+		if err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		if err == nil {
+			removed++
+		}
+	}
+
+	files := make([]string, 0, len(produced))
+	for rel := range produced {
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return removed, err
+	}
+	return removed, os.WriteFile(path, []byte(strings.Join(files, "\n")+"\n"), 0644)
+}
+
+// sessionAt finds the last entry logged before t, which tells what was being
+// worked on then.
+func sessionAt(entries []breaklog.BreakLogEntry, t time.Time) (breaklog.BreakLogEntry, bool) {
+	var last breaklog.BreakLogEntry
+	found := false
+	for _, e := range entries {
+		if e.Timestamp.After(t) {
+			break
+		}
+		last = e
+		found = true
+	}
+	return last, found
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/journal"
+	"github.com/SamD2021/boba-break/internal/notes"
+)
+
+func TestExport(t *testing.T) {
+	store, err := notes.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Mine.md"), []byte("my own note"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now()
+	entries := []breaklog.BreakLogEntry{
+		{Timestamp: at, Kind: breaklog.ScribbleEntry, Findings: "local"},
+		// The same day seen from another location
+		{Timestamp: at.UTC(), Kind: breaklog.ScribbleEntry, Findings: "utc"},
+	}
+
+	steps := []struct {
+		name    string
+		change  func() error
+		want    Report
+		exist   []string
+		missing []string
+	}{
+		{
+			name:   "first export",
+			change: func() error { return store.Write("Ideas", "coffee") },
+			want:   Report{Written: 2},
+			exist:  []string{"Ideas.md", "Mine.md", filepath.Join(JournalDir, journal.Name(at)+".md")},
+		},
+		{
+			name:   "nothing changed",
+			change: func() error { return nil },
+			want:   Report{Unchanged: 2},
+		},
+		{
+			name:    "renamed",
+			change:  func() error { return store.Rename("Ideas", "Plans") },
+			want:    Report{Written: 2, Removed: 1},
+			exist:   []string{"Plans.md", "Mine.md"},
+			missing: []string{"Ideas.md"},
+		},
+		{
+			name:    "deleted",
+			change:  func() error { return store.Delete("Plans") },
+			want:    Report{Written: 1, Removed: 1},
+			exist:   []string{"Mine.md"},
+			missing: []string{"Plans.md"},
+		},
+	}
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		report, err := Export(dir, store, entries)
+		if err != nil {
+			t.Fatalf("%s: Export() = %v", step.name, err)
+		}
+		if report != step.want {
+			t.Errorf("%s: Export() = %+v, want %+v", step.name, report, step.want)
+		}
+		for _, f := range step.exist {
+			if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+				t.Errorf("%s: %v", step.name, err)
+			}
+		}
+		for _, f := range step.missing {
+			if _, err := os.Stat(filepath.Join(dir, f)); !os.IsNotExist(err) {
+				t.Errorf("%s: %s is still there", step.name, f)
+			}
+		}
+	}
+}
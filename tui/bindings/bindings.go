@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+// Package bindings builds the views' key bindings from the keys in the
+// config, with help that shows the keys actually bound.
+package bindings
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Arrow keys are shown as arrows in the help, the space bar by name.
+var symbols = map[string]string{
+	" ":     "space",
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// New binds keys to an action described as desc in the help.
+func New(keys []string, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(Label(keys), desc))
+}
+
+// Label is how keys are shown in the help, like "↑/k".
+func Label(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if s, ok := symbols[k]; ok {
+			k = s
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
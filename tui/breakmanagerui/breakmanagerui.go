@@ -24,10 +24,21 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/parkinglot"
+	"github.com/SamD2021/boba-break/internal/project"
+	"github.com/SamD2021/boba-break/internal/task"
+	"github.com/SamD2021/boba-break/tui/bindings"
+	"github.com/SamD2021/boba-break/tui/layout"
 	"github.com/SamD2021/boba-break/tui/mainmenuui"
+	"github.com/SamD2021/boba-break/tui/theme"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/timer"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -44,12 +55,6 @@ func tickCmd(t time.Time) tea.Msg {
 	return tickMsg(t)
 }
 
-var (
-	red    = lipgloss.AdaptiveColor{Light: "#FE5F86", Dark: "#FE5F86"}
-	indigo = lipgloss.AdaptiveColor{Light: "#5A56E0", Dark: "#7571F9"}
-	green  = lipgloss.AdaptiveColor{Light: "#02BA84", Dark: "#02BF87"}
-)
-
 type Styles struct {
 	Base,
 	HeaderText,
@@ -57,49 +62,52 @@ type Styles struct {
 	StatusHeader,
 	Highlight,
 	ErrorHeaderText,
-	Help lipgloss.Style
+	Help,
+	Resume,
+	Sidebar,
+	SidebarTime,
+	Focus,
+	Break,
+	Error lipgloss.Style
 }
 
-func NewStyles(lg *lipgloss.Renderer) *Styles {
+func NewStyles(lg *lipgloss.Renderer, t theme.Theme) *Styles {
+	common := t.Styles()
 	s := Styles{}
 	s.Base = lg.NewStyle().
 		Padding(1, 4, 0, 1)
 	s.HeaderText = lg.NewStyle().
-		Foreground(indigo).
+		Foreground(t.Primary).
 		Bold(true).
 		Padding(0, 1, 0, 2)
 	s.Status = lg.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(indigo).
+		BorderForeground(t.Primary).
 		PaddingLeft(1).
 		MarginTop(1)
-	s.StatusHeader = lg.NewStyle().
-		Foreground(green).
-		Bold(true)
-	s.Highlight = lg.NewStyle().
-		Foreground(lipgloss.Color("212"))
+	s.StatusHeader = common.Label.Copy()
+	s.Highlight = common.Highlight.Copy()
 	s.ErrorHeaderText = s.HeaderText.Copy().
-		Foreground(red)
-	s.Help = lg.NewStyle().
-		Foreground(lipgloss.Color("240"))
+		Foreground(t.Error)
+	s.Help = common.Muted.Copy()
+	s.Resume = lg.NewStyle().
+		Border(lipgloss.ThickBorder(), false, false, false, true).
+		BorderForeground(t.Accent).
+		Margin(1, 1, 0, 1).
+		PaddingLeft(1).
+		Width(46)
+	s.Sidebar = lg.NewStyle().
+		MarginLeft(3).
+		Padding(1, 3).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Muted)
+	s.SidebarTime = common.Muted.Copy()
+	s.Focus = common.Focus.Copy()
+	s.Break = common.Break.Copy()
+	s.Error = common.Error.Copy()
 	return &s
 }
 
-const (
-	focusColor = "#2EF8BB"
-	breakColor = "#FF5F87"
-)
-
-var (
-	focusTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(focusColor)).MarginRight(1).SetString("Focus Mode")
-	breakTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(breakColor)).MarginRight(1).SetString("Break Mode")
-	pausedStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color(breakColor)).MarginRight(1).SetString("Continue?")
-	helpStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).MarginTop(2)
-	sidebarStyle    = lipgloss.NewStyle().MarginLeft(3).Padding(1, 3).Border(lipgloss.RoundedBorder()).BorderForeground(helpStyle.GetForeground())
-)
-
-var baseTimerStyle = lipgloss.NewStyle().Padding(1, 2)
-
 type sessionState int
 
 const (
@@ -125,9 +133,58 @@ type BreakModel struct {
 	count      int8
 	scribble   *scribble
 	scribbling bool
+	// Last error saving to disk, shown until the next key
+	err error
+	// Whether the timer was running when the scribble paused it
+	resumeAfterScribble bool
+	// When the running timer last ticked, to make up for the time the
+	// program is held up in this view
+	lastTick   time.Time
 	lg         *lipgloss.Renderer
 	styles     *Styles
 	width      int
+	height     int
+	logger     *breaklog.FileBreakLogger
+	tasks      *task.FileTaskStore
+	task       string
+	project    string
+	tags       []string
+	taskPicker *taskPicker
+	picking    bool
+	session    string
+	prompt     *linePrompt
+	parked     *parkinglot.FileParkingLot
+	notes      *notes.Store
+	triage     *triage
+	// Focus phase logged, waiting for the user to say where they left off
+	pending     *breaklog.BreakLogEntry
+	leftOff     string // Latest work in progress noted at the end of focus
+	resume      string // Work in progress shown during the current focus session
+	sidebar     viewport.Model
+	showSidebar bool
+	// Interruptions logged during the current session
+	interruptions map[breaklog.InterruptionType]int
+	notifications config.Notifications
+	// Every longBreakEvery focus sessions the break lasts longBreak instead
+	longBreak      time.Duration
+	longBreakEvery int
+	// Whether the next phase starts by itself when one ends
+	autoStart bool
+	// Timer profile in use, recorded with each phase
+	profile string
+	theme   theme.Theme
+	// How the time left and the phase's progress are shown, as in the
+	// config's [display] table
+	clock    string
+	progress string
+	// Whether breaks get a screen of their own, and what it suggests doing
+	fullScreen  bool
+	suggestions []string
+	rotate      time.Duration
+	routine     config.Routine
+	// Suggestions and routine time skipped during the current break
+	skips   int
+	skipped time.Duration
 }
 
 type keymap struct {
@@ -137,22 +194,100 @@ type keymap struct {
 	quit     key.Binding
 	back     key.Binding
 	scribble key.Binding
+	pickTask key.Binding
+	internal key.Binding
+	external key.Binding
+	park     key.Binding
+	early    key.Binding
+	next     key.Binding
+	history  key.Binding
+	scrollUp key.Binding
+	scrollDn key.Binding
+}
+
+// newKeymap binds the actions to keys, by action name as in the config.
+func newKeymap(keys map[string][]string) keymap {
+	return keymap{
+		start:    bindings.New(keys["start"], "start"),
+		stop:     bindings.New(keys["start"], "stop"),
+		reset:    bindings.New(keys["reset"], "reset"),
+		quit:     bindings.New(keys["quit"], "quit"),
+		back:     bindings.New(keys["back"], "back"),
+		scribble: bindings.New(keys["scribble"], "scribble"),
+		pickTask: bindings.New(keys["task"], "task"),
+		internal: bindings.New(keys["internal"], "internal"),
+		external: bindings.New(keys["external"], "external"),
+		park:     bindings.New(keys["park"], "park"),
+		early:    bindings.New(keys["back_early"], "back early"),
+		next:     bindings.New(keys["next"], "next"),
+		history:  bindings.New(keys["history"], "history"),
+		scrollUp: bindings.New(keys["scroll_up"], "scroll up"),
+		scrollDn: bindings.New(keys["scroll_down"], "scroll down"),
+	}
 }
 
 func (m BreakModel) Init() tea.Cmd {
 
-	return tea.Batch(m.Timer.Init(), m.scribble.form.Init())
+	return m.Timer.Init()
 }
 
 func (m BreakModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	// ctrl+c always quits, the prompts and forms below would take it as
+	// typing.
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyCtrlC {
+		m.done = true
+		return m, tea.Quit
+	}
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.err = nil
+	}
+	if m.picking {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.Type == tea.KeyEsc {
+				m.picking = false
+				return m, nil
+			}
+			return m.updateTaskPicker(msg)
+		}
+	}
+	if m.prompt != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.updatePrompt(msg)
+		}
+	}
+	if m.scribbling {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.Type == tea.KeyEsc {
+				return m, m.closeScribble()
+			}
+			return m.updateScribble(msg)
+		}
+	}
+	if m.triage != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.Type == tea.KeyEsc {
+				m.triage = nil
+				return m, nil
+			}
+			return m.updateTriage(msg)
+		}
+	}
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		return m, nil
+
 	case timer.TickMsg:
+		if msg.ID == m.Timer.ID() && m.Timer.Running() {
+			m.catchUp(time.Now())
+		}
 		m.Timer, cmd = m.Timer.Update(msg)
 		return m, cmd
 
 	case timer.StartStopMsg:
+		m.lastTick = time.Time{}
 		m.Timer, cmd = m.Timer.Update(msg)
 		m.keymap.stop.SetEnabled(m.Timer.Running())
 		m.keymap.start.SetEnabled(!m.Timer.Running())
@@ -160,26 +295,23 @@ func (m BreakModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case timer.TimeoutMsg:
 		var switchmsg tea.Cmd
+		var contextCmd tea.Cmd
 		m.done = true
 		m.Timer, cmd = m.Timer.Update(msg)
 		switch m.state {
 		case Focusing:
-			icon := ""
-			title := "Boba Time"
-			message := "Time is up, Enjoy some Boba!"
-			err := beeep.Alert(title, message, icon)
-			if err != nil {
-				fmt.Println("Error sending message: ", err)
+			// The focus phase is logged right away so quitting doesn't lose
+			// it, where the user left off is added once they say.
+			m.pending = breaklog.NewPhaseEntry(breaklog.FocusPhase, m.workTime)
+			m.record(m.pending)
+			if m.prompt == nil && !m.scribbling {
+				m.prompt = newContextPrompt()
+				contextCmd = textinput.Blink
 			}
 		case Relaxing:
-			icon := ""
-			title := "Get Working!"
-			message := "Lets put the cup down and get busy!"
-			err := beeep.Alert(title, message, icon)
-			if err != nil {
-				fmt.Println("Error sending message: ", err)
-			}
+			m.record(breaklog.NewPhaseEntry(breaklog.BreakPhase, m.breakLength()))
 		}
+		m.notify()
 		m.keymap.stop.SetEnabled(m.Timer.Running())
 		m.keymap.start.SetEnabled(!m.Timer.Running())
 		switch m.state {
@@ -195,7 +327,7 @@ func (m BreakModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return SwitchWorkMsg{}
 			}
 		}
-		return m, tea.Batch(cmd, switchmsg)
+		return m, tea.Batch(cmd, switchmsg, contextCmd)
 
 	case tea.KeyMsg:
 		switch {
@@ -204,12 +336,7 @@ func (m BreakModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case key.Matches(msg, m.keymap.reset):
 			var cmds []tea.Cmd
-			switch m.state {
-			case Focusing:
-				m.Timer.Timeout = m.workTime
-			case Relaxing:
-				m.Timer.Timeout = m.breakTime
-			}
+			m.Timer.Timeout = m.phaseLength()
 			m.done = false
 			cmd = m.Timer.Stop()
 			cmds = append(cmds, cmd)
@@ -224,58 +351,333 @@ func (m BreakModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return BackMsg{}
 				}
 		case key.Matches(msg, m.keymap.scribble):
-			cmd = m.Timer.Toggle()
+			m.resumeAfterScribble = m.Timer.Running()
+			if m.resumeAfterScribble {
+				cmd = m.Timer.Stop()
+			}
 			return m, tea.Batch(cmd, func() tea.Msg {
 				return ScribblingMsg{}
 			})
-
+		case key.Matches(msg, m.keymap.pickTask):
+			m.taskPicker = newTaskPicker(m.tasks.Open(), m.task)
+			m.styleForm(m.taskPicker.form)
+			m.picking = true
+			return m, m.taskPicker.form.Init()
+		case key.Matches(msg, m.keymap.internal):
+			m.prompt = newLinePrompt(internalInterruptionPrompt, "Internal interruption", "what pulled you away? (optional)")
+			return m, textinput.Blink
+		case key.Matches(msg, m.keymap.external):
+			m.prompt = newLinePrompt(externalInterruptionPrompt, "External interruption", "who or what interrupted? (optional)")
+			return m, textinput.Blink
+		case key.Matches(msg, m.keymap.history):
+			m.showSidebar = !m.showSidebar
+			m.keymap.scrollUp.SetEnabled(m.showSidebar)
+			m.keymap.scrollDn.SetEnabled(m.showSidebar)
+			m.sidebar.GotoTop()
+			return m, nil
+		case key.Matches(msg, m.keymap.scrollUp):
+			m.sidebar.SetContent(historyView(m.logger.Entries(), m.sidebar.Width, m.styles))
+			m.sidebar.LineUp(1)
+			return m, nil
+		case key.Matches(msg, m.keymap.scrollDn):
+			m.sidebar.SetContent(historyView(m.logger.Entries(), m.sidebar.Width, m.styles))
+			m.sidebar.LineDown(1)
+			return m, nil
+		case key.Matches(msg, m.keymap.park):
+			m.prompt = newLinePrompt(parkPrompt, "Park a thought for the break", "one line, the timer keeps running")
+			return m, textinput.Blink
+		case key.Matches(msg, m.keymap.early):
+			return m, m.backEarly()
+		case key.Matches(msg, m.keymap.next):
+			m.skipActivity()
+			return m, nil
 		}
 	case mainmenuui.SelectedBreakManagerMsg:
+		// The timer got no ticks in the other views, that time isn't made
+		// up for.
+		m.lastTick = time.Time{}
 		// m.Timer, cmd = m.Timer.Update(timer.TickMsg{})
 		return m, m.Timer.Init()
 	case SwitchWorkMsg:
-		m.state = Focusing
-		m.Timer.Timeout = m.workTime
-		m.done = false
-		cmd = m.Timer.Stop()
+		m.switchToWork()
+		cmd = m.nextPhase()
 		m.keymap.stop.SetEnabled(m.Timer.Running())
 		m.keymap.start.SetEnabled(!m.Timer.Running())
 		return m, cmd
 	case SwitchBreakMsg:
 		m.state = Relaxing
-		m.Timer.Timeout = m.breakTime
+		m.Timer.Timeout = m.breakLength()
 		m.done = false
-		cmd = m.Timer.Stop()
+		m.skips, m.skipped = 0, 0
+		m.phaseKeys()
+		cmd = m.nextPhase()
 		m.keymap.stop.SetEnabled(m.Timer.Running())
 		m.keymap.start.SetEnabled(!m.Timer.Running())
+		if items := m.parked.Items(); len(items) > 0 {
+			m.triage = newTriage(items, m.theme.Form())
+			m.styleForm(m.triage.form)
+			return m, tea.Batch(cmd, m.triage.form.Init())
+		}
 		return m, cmd
 	case ScribblingMsg:
+		m.scribble = New(m.phase(), m.project, m.tags)
+		m.styleForm(m.scribble.form)
 		m.scribbling = true
-		err := m.scribble.form.Run()
-		if err != nil {
-			return nil, nil
-		}
-		cmds = append(cmds,
-			func() tea.Msg {
-				return timer.TickMsg{}
-			})
+		return m, m.scribble.form.Init()
+	}
+	if m.picking {
+		return m.updateTaskPicker(msg)
+	}
+	if m.triage != nil {
+		return m.updateTriage(msg)
 	}
-	// Process the form
+	if m.scribbling {
+		return m.updateScribble(msg)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m BreakModel) updateScribble(msg tea.Msg) (tea.Model, tea.Cmd) {
 	form, cmd := m.scribble.form.Update(msg)
 	if f, ok := form.(*huh.Form); ok {
-		m.scribble.form = *f
-		cmds = append(cmds, cmd)
+		m.scribble.form = f
 	}
-	if m.scribble.form.State == huh.StateCompleted {
-		// Quit when the form is done.
-		m.scribbling = false
-		m.scribble.log()
-		m.scribble = New()
-		//cmd = m.Timer.Toggle()
-		cmds = append(cmds, cmd)
+	switch m.scribble.form.State {
+	case huh.StateCompleted:
+		// Whatever was entered on the scribble carries over to the session.
+		m.project = strings.TrimSpace(m.scribble.project)
+		m.tags = breaklog.ParseTags(m.scribble.tags)
+		entry := m.scribble.entry()
+		m.record(entry)
+		if entry.WorkInProgress != "" {
+			m.leftOff = entry.WorkInProgress
+		}
+		return m, tea.Batch(cmd, m.closeScribble())
+	case huh.StateAborted:
+		return m, tea.Batch(cmd, m.closeScribble())
 	}
+	return m, cmd
+}
 
-	return m, tea.Batch(cmds...)
+// closeScribble hides the scribble form, restarts the timer if the scribble
+// paused it and asks where the user left off if focus ended meanwhile.
+func (m *BreakModel) closeScribble() tea.Cmd {
+	m.scribbling = false
+	m.scribble = nil
+	cmd := m.askPending()
+	if !m.resumeAfterScribble {
+		return cmd
+	}
+	m.resumeAfterScribble = false
+	return tea.Batch(cmd, m.Timer.Start())
+}
+
+// catchUp takes the time that went by without ticks off the running timer,
+// while the program was held up in the timer's own view. Running out of
+// time ends the phase with the next tick.
+func (m *BreakModel) catchUp(now time.Time) {
+	if !m.lastTick.IsZero() {
+		if missed := now.Sub(m.lastTick) - m.Timer.Interval; missed > m.Timer.Interval {
+			m.Timer.Timeout = max(m.Timer.Timeout-missed, m.Timer.Interval)
+		}
+	}
+	m.lastTick = now
+}
+
+func (m BreakModel) updateTaskPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.taskPicker.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.taskPicker.form = f
+	}
+	switch m.taskPicker.form.State {
+	case huh.StateCompleted:
+		m.task = m.taskPicker.selected
+		m.picking = false
+	case huh.StateAborted:
+		m.picking = false
+	}
+	return m, cmd
+}
+
+func (m BreakModel) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.prompt.kind == contextPrompt {
+			m.recordPending("")
+		}
+		m.prompt = nil
+		return m, m.askPending()
+	case tea.KeyEnter:
+		note := strings.TrimSpace(m.prompt.input.Value())
+		switch m.prompt.kind {
+		case contextPrompt:
+			m.recordPending(note)
+		case internalInterruptionPrompt:
+			m.recordInterruption(breaklog.InternalInterruption, note)
+		case externalInterruptionPrompt:
+			m.recordInterruption(breaklog.ExternalInterruption, note)
+		case parkPrompt:
+			if note != "" {
+				m.err = m.parked.Add(note, m.session)
+			}
+		}
+		m.prompt = nil
+		return m, m.askPending()
+	}
+	m.prompt.input, cmd = m.prompt.input.Update(msg)
+	return m, cmd
+}
+
+func (m BreakModel) updateTriage(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.triage.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.triage.form = f
+	}
+	switch m.triage.form.State {
+	case huh.StateCompleted:
+		m.triageItem(m.triage.current(), m.triage.action)
+		if !m.triage.advance() {
+			m.triage = nil
+			return m, cmd
+		}
+		m.styleForm(m.triage.form)
+		return m, tea.Batch(cmd, m.triage.form.Init())
+	case huh.StateAborted:
+		m.triage = nil
+	}
+	return m, cmd
+}
+
+// triageItem does what was decided for a parked item and takes it out of the
+// parking lot unless it was left for later.
+func (m *BreakModel) triageItem(item parkinglot.Item, action triageAction) {
+	var err error
+	switch action {
+	case triageLater:
+		return
+	case triageTask:
+		_, err = m.tasks.Add(item.Text, 0)
+	case triageNote:
+		err = m.notes.Append(parkedNote, "- "+item.Text)
+	}
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.err = m.parked.Remove(item.ID)
+}
+
+func (m *BreakModel) recordInterruption(kind breaklog.InterruptionType, note string) {
+	m.interruptions[kind]++
+	m.record(breaklog.NewInterruptionEntry(kind, note))
+}
+
+// recordPending adds where the user left off to the focus phase that ended,
+// and keeps it to be shown when the next focus session starts.
+func (m *BreakModel) recordPending(leftOff string) {
+	if m.pending == nil {
+		return
+	}
+	if leftOff != "" {
+		m.pending.WorkInProgress = leftOff
+		if err := m.logger.UpdateLogEntry(m.pending); err != nil {
+			m.err = err
+		}
+		m.leftOff = leftOff
+	}
+	m.pending = nil
+}
+
+// askPending opens the context prompt if a focus phase ended while another
+// prompt was open.
+func (m *BreakModel) askPending() tea.Cmd {
+	if m.pending == nil || m.prompt != nil {
+		return nil
+	}
+	m.prompt = newContextPrompt()
+	return textinput.Blink
+}
+
+// switchToWork starts a new focus session, leaving the timer to be started
+// or stopped.
+func (m *BreakModel) switchToWork() {
+	m.state = Focusing
+	m.session = breaklog.NewSessionID(time.Now())
+	m.interruptions = map[breaklog.InterruptionType]int{}
+	m.resume = m.leftOff
+	m.Timer.Timeout = m.workTime
+	m.done = false
+	m.phaseKeys()
+}
+
+// phaseKeys turns on the keys that do something in the current phase. The
+// break screen leaves the task, interruptions, parking lot and history to
+// focus sessions.
+func (m *BreakModel) phaseKeys() {
+	relaxing := m.state == Relaxing
+	m.keymap.early.SetEnabled(relaxing)
+	m.keymap.next.SetEnabled(relaxing && (len(m.suggestions) > 0 || len(m.routine.Steps) > 0))
+	focus := !m.breakScreen()
+	m.keymap.pickTask.SetEnabled(focus)
+	m.keymap.internal.SetEnabled(focus)
+	m.keymap.external.SetEnabled(focus)
+	m.keymap.park.SetEnabled(focus)
+	m.keymap.history.SetEnabled(focus)
+	m.keymap.scrollUp.SetEnabled(focus && m.showSidebar)
+	m.keymap.scrollDn.SetEnabled(focus && m.showSidebar)
+}
+
+// nextPhase starts the phase that was just switched to if auto start is on,
+// and otherwise waits for the user to start it.
+func (m *BreakModel) nextPhase() tea.Cmd {
+	if m.autoStart {
+		return m.Timer.Start()
+	}
+	return m.Timer.Stop()
+}
+
+// breakLength is how long the break after the current focus session lasts.
+func (m BreakModel) breakLength() time.Duration {
+	if m.longBreakEvery > 0 && int(m.count)%m.longBreakEvery == 0 {
+		return m.longBreak
+	}
+	return m.breakTime
+}
+
+// phaseLength is how long the current phase lasts in full.
+func (m BreakModel) phaseLength() time.Duration {
+	if m.state == Relaxing {
+		return m.breakLength()
+	}
+	return m.workTime
+}
+
+func (m BreakModel) phase() breaklog.Phase {
+	if m.state == Relaxing {
+		return breaklog.BreakPhase
+	}
+	return breaklog.FocusPhase
+}
+
+// record labels entry with the current session, phase, task, project, tags
+// and profile, then writes it to the break log. A failure is kept to be shown
+// and returned.
+func (m *BreakModel) record(entry *breaklog.BreakLogEntry) error {
+	entry.Session = m.session
+	entry.Task = m.task
+	entry.Project = m.project
+	entry.Tags = m.tags
+	entry.Profile = m.profile
+	if entry.Phase == "" {
+		entry.Phase = m.phase()
+	}
+	err := m.logger.AddLogEntry(entry)
+	if err != nil {
+		m.err = err
+	}
+	return err
 }
 
 func (m BreakModel) helpView() string {
@@ -286,10 +688,43 @@ func (m BreakModel) helpView() string {
 		m.keymap.quit,
 		m.keymap.back,
 		m.keymap.scribble,
+		m.keymap.pickTask,
+		m.keymap.internal,
+		m.keymap.external,
+		m.keymap.park,
+		m.keymap.early,
+		m.keymap.next,
+		m.keymap.history,
+		m.keymap.scrollUp,
+		m.keymap.scrollDn,
 	})
 }
 
 func (m BreakModel) View() string {
+	if m.size() == layout.Compact {
+		return m.compactView()
+	}
+	if m.breakScreen() && !m.busy() {
+		view := m.breakView(true)
+		if m.height > 0 && lipgloss.Height(view) > m.height {
+			view = m.breakView(false)
+		}
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
+	}
+	view := m.fullView(true)
+	if m.height > 0 && lipgloss.Height(view) > m.height {
+		view = m.fullView(false)
+	}
+	if m.size() == layout.Large {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, view)
+	}
+	return view
+}
+
+// fullView is the break manager with room around it. Unless roomy is set
+// the clock and progress keep to a line each, for terminals too short for
+// the big digits or the cup.
+func (m BreakModel) fullView(roomy bool) string {
 	// For a more detailed timer view you could read m.timer.Timeout to get
 	// the remaining time as a time.Duration and skip calling m.timer.View()
 	// entirely.
@@ -297,7 +732,7 @@ func (m BreakModel) View() string {
 	var timer string
 	var scribble string
 
-	v := strings.TrimSuffix(m.TimerView(), "\n\n")
+	v := strings.TrimSuffix(m.timerView(roomy), "\n\n")
 	timer = m.lg.NewStyle().Margin(1, 0).Render(v)
 	styles := m.styles
 	// // var sb strings.Builder
@@ -309,33 +744,107 @@ func (m BreakModel) View() string {
 		scribble = m.lg.NewStyle().Margin(1, 1).Render(sv)
 		body = lipgloss.JoinVertical(lipgloss.Top, timer, scribble)
 		footer = m.appBoundaryView(m.scribble.form.Help().ShortHelpView(m.scribble.form.KeyBinds()))
+	} else if m.prompt != nil {
+		pv := m.styles.StatusHeader.Render(m.prompt.title) + "\n" + m.prompt.input.View()
+		prompt := m.lg.NewStyle().Margin(1, 1).Render(pv)
+		body = lipgloss.JoinVertical(lipgloss.Top, timer, prompt)
+		footer = m.appBoundaryView(m.help.ShortHelpView([]key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "save")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		}))
+	} else if m.triage != nil {
+		tv := strings.TrimSuffix(m.triage.form.View(), "\n\n")
+		triage := m.lg.NewStyle().Margin(1, 1).Render(tv)
+		body = lipgloss.JoinVertical(lipgloss.Top, timer, triage)
+		footer = m.appBoundaryView(m.triage.form.Help().ShortHelpView(m.triage.form.KeyBinds()))
+	} else if m.picking {
+		pv := strings.TrimSuffix(m.taskPicker.form.View(), "\n\n")
+		picker := m.lg.NewStyle().Margin(1, 1).Render(pv)
+		body = lipgloss.JoinVertical(lipgloss.Top, timer, picker)
+		footer = m.appBoundaryView(m.taskPicker.form.Help().ShortHelpView(m.taskPicker.form.KeyBinds()))
 	} else {
 		body = lipgloss.JoinVertical(lipgloss.Top, timer)
 		footer = m.appBoundaryView(m.helpView())
 	}
-	// if len(errors) > 0 {
-	// 	footer = m.appErrorBoundaryView("")
-	// }
+	if m.err != nil {
+		footer = m.appErrorBoundaryView(m.err.Error()) + "\n" + footer
+	}
+	if m.resume != "" && m.state == Focusing {
+		resume := styles.Resume.Copy().Width(m.statusWidth() - 2).Render(styles.StatusHeader.Render("Where you left off") + "\n" + m.resume)
+		body = lipgloss.JoinVertical(lipgloss.Top, resume, body)
+	}
+	if m.showSidebar {
+		sidebar := m.sidebar
+		sidebar.SetContent(historyView(m.logger.Entries(), m.sidebar.Width, m.styles))
+		// The history takes the rows left over, beside the status box when
+		// there is room for it and under it otherwise.
+		chrome := styles.Sidebar.GetVerticalFrameSize() + 2
+		if m.sidebarBeside() {
+			if m.height > 0 {
+				rest := m.height - lipgloss.Height(styles.Base.Render(header+"\n\n\n"+footer))
+				sidebar.Height = max(min(sidebarHeight, rest-chrome), 1)
+			}
+			history := styles.StatusHeader.Render("Today") + "\n\n" + sidebar.View()
+			body = lipgloss.JoinHorizontal(lipgloss.Top, body, styles.Sidebar.Render(history))
+		} else {
+			if m.height > 0 {
+				rest := m.height - lipgloss.Height(styles.Base.Render(header+"\n"+body+"\n\n"+footer))
+				sidebar.Height = max(min(sidebarHeight, rest-chrome), 1)
+			}
+			history := styles.StatusHeader.Render("Today") + "\n\n" + sidebar.View()
+			body = lipgloss.JoinVertical(lipgloss.Top, body, styles.Sidebar.Copy().MarginLeft(1).Render(history))
+		}
+	}
 	return styles.Base.Render(header + "\n" + body + "\n\n" + footer)
 }
 
 func (m BreakModel) TimerView() string {
+	return m.timerView(true)
+}
+
+// timerView is the status box. The clock is drawn in big digits and the
+// progress as a cup only if roomy is set and there is room for them.
+func (m BreakModel) timerView(roomy bool) string {
 	styles := m.styles
-	ms := m.Timer.Timeout.Milliseconds()
-	hours := ms / (3.6e+6)
-	ms -= hours
-	minutes := ms / 60000
-	ms -= minutes
-	seconds := ms / 1000
-	// remainingMs := ms % 1000 // Capture the remainder milliseconds
-	var s string
-	switch m.state {
-	case Focusing:
-		s = fmt.Sprintf("Work Session: %v\n", m.count)
-	case Relaxing:
-		s = fmt.Sprintf("Break Session: %v\n", m.count)
-	}
-	s += styles.Highlight.Render(fmt.Sprintf("%v h %v m %v s", hours%60, minutes%60, seconds%60))
+	box := styles.Status.Copy().Margin(0, 1).Padding(1, 2).Width(m.statusWidth())
+	inner := m.statusWidth() - box.GetHorizontalPadding()
+	s := m.sessionTitle() + "\n"
+	switch {
+	case m.progress == "cup" && roomy:
+		// The cup goes beside the clock, which keeps to a line if the big
+		// digits would push it out.
+		cup := m.cupView()
+		gap := "   "
+		s += m.clockView(inner-lipgloss.Width(gap+cup), true)
+		s = lipgloss.JoinHorizontal(lipgloss.Bottom, s, gap, cup)
+	case m.progress == "none":
+		s += m.clockView(inner, roomy)
+	default:
+		s += m.clockView(inner, roomy) + "\n" + m.progressBar(inner)
+	}
+	if label, text := m.activity(); m.state == Relaxing && text != "" {
+		s += "\n" + styles.StatusHeader.Render(label) + "\n" + text
+	}
+	if m.task != "" {
+		s += "\n" + styles.StatusHeader.Render("Task: ") + m.task
+	}
+	if m.project != "" {
+		s += "\n" + styles.StatusHeader.Render("Project: ") + m.project
+	}
+	if len(m.tags) > 0 {
+		s += "\n" + styles.StatusHeader.Render("Tags: ") + strings.Join(m.tags, ", ")
+	}
+	if m.profile != "" {
+		s += "\n" + styles.StatusHeader.Render("Profile: ") + m.profile
+	}
+	if parked := len(m.parked.Items()); parked > 0 {
+		s += "\n" + styles.StatusHeader.Render("Parked: ") + fmt.Sprintf("%d for the next break", parked)
+	}
+	internal := m.interruptions[breaklog.InternalInterruption]
+	external := m.interruptions[breaklog.ExternalInterruption]
+	if internal+external > 0 {
+		s += "\n" + styles.StatusHeader.Render("Interruptions: ") + fmt.Sprintf("%d internal, %d external", internal, external)
+	}
 
 	// if m.Timer.Timedout() && m.state == Focusing {
 	// 	s = "Go take a break!"
@@ -345,57 +854,208 @@ func (m BreakModel) TimerView() string {
 	// 	s += m.helpView()
 	// }
 	// s += "\n"
-	return styles.Status.Copy().Margin(0, 1).Padding(1, 2).Width(48).Render(s) + "\n\n"
+	return box.Render(s) + "\n\n"
 }
 func (m BreakModel) appBoundaryView(text string) string {
 	return lipgloss.PlaceHorizontal(
-		m.width,
+		m.contentWidth(),
 		lipgloss.Left,
 		m.styles.HeaderText.Render(text),
 		lipgloss.WithWhitespaceChars("/"),
-		lipgloss.WithWhitespaceForeground(indigo),
+		lipgloss.WithWhitespaceForeground(m.theme.Primary),
 	)
 }
 func (m BreakModel) appErrorBoundaryView(text string) string {
 	return lipgloss.PlaceHorizontal(
-		m.width,
+		m.contentWidth(),
 		lipgloss.Left,
 		m.styles.ErrorHeaderText.Render(text),
 		lipgloss.WithWhitespaceChars("/"),
-		lipgloss.WithWhitespaceForeground(red),
+		lipgloss.WithWhitespaceForeground(m.theme.Error),
 	)
 }
 
 func InitialModel(workDuration time.Duration, breakDuration time.Duration) BreakModel {
+	logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+	if err != nil {
+		panic(err)
+	}
+	tasks, err := task.NewFileTaskStore(task.DefaultFilePath)
+	if err != nil {
+		panic(err)
+	}
+	defaults := project.ForCurrentDir()
+	parked, err := parkinglot.NewFileParkingLot(parkinglot.DefaultFilePath)
+	if err != nil {
+		panic(err)
+	}
+	noteStore, err := notes.NewStore(notes.DefaultDir)
+	if err != nil {
+		panic(err)
+	}
+	leftOff := lastWorkInProgress(logger.Entries())
 	m := BreakModel{
-		width: maxWidth,
-		help:  help.New(),
-		keymap: keymap{
-			start:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "start")),
-			stop:     key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "stop")),
-			reset:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reset")),
-			quit:     key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-			back:     key.NewBinding(key.WithKeys("backspace"), key.WithHelp("backspace", "back")),
-			scribble: key.NewBinding(key.WithKeys("n"), key.WithHelp("scribble", "n")),
-		},
-		Timer:      timer.NewWithInterval(workDuration, tickInterval),
-		done:       false,
-		workTime:   workDuration,
-		breakTime:  breakDuration,
-		state:      Focusing,
-		count:      1,
-		scribble:   New(),
-		lg:         lipgloss.DefaultRenderer(),
-		styles:     NewStyles(lipgloss.DefaultRenderer()),
-		scribbling: false,
+		width:         maxWidth,
+		help:          help.New(),
+		keymap:        newKeymap(config.Default().KeysOf("break")),
+		Timer:         timer.NewWithInterval(workDuration, tickInterval),
+		done:          false,
+		workTime:      workDuration,
+		breakTime:     breakDuration,
+		state:         Focusing,
+		count:         1,
+		logger:        logger,
+		tasks:         tasks,
+		parked:        parked,
+		notes:         noteStore,
+		leftOff:       leftOff,
+		resume:        leftOff,
+		sidebar:       newSidebar(),
+		project:       defaults.Project,
+		tags:          defaults.Tags,
+		session:       breaklog.NewSessionID(time.Now()),
+		interruptions: map[breaklog.InterruptionType]int{},
+		notifications: config.Default().Notifications,
+		clock:         config.Default().Display.Clock,
+		progress:      config.Default().Display.Progress,
+		fullScreen:    config.Default().Breaks.FullScreen,
+		suggestions:   config.Default().Breaks.Suggestions,
+		rotate:        config.Default().Breaks.Rotate.Duration,
+		lg:            lipgloss.DefaultRenderer(),
+		styles:        NewStyles(lipgloss.DefaultRenderer(), theme.Default()),
+		theme:         theme.Default(),
+		scribbling:    false,
 	}
 	m.keymap.stop.SetEnabled(true)
 	m.keymap.start.SetEnabled(false)
+	m.phaseKeys()
 	// m.keymap.scribble.SetEnabled(false)
 	m.done = false
 	return m
 }
 
+// lastWorkInProgress finds where the user left off at the end of their most
+// recent focus session, so a new run of the break manager can show it too.
+func lastWorkInProgress(entries []breaklog.BreakLogEntry) string {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].WorkInProgress != "" {
+			return entries[i].WorkInProgress
+		}
+	}
+	return ""
+}
+
+// WithTask sets the task that focus sessions are recorded against.
+func (m BreakModel) WithTask(name string) BreakModel {
+	m.task = name
+	return m
+}
+
+// WithProject overrides the project sessions and scribbles are labelled with.
+func (m BreakModel) WithProject(name string) BreakModel {
+	m.project = name
+	return m
+}
+
+// Task is the task focus sessions are recorded against, if any.
+func (m BreakModel) Task() string {
+	return m.task
+}
+
+// Project is the project sessions are labelled with, if any.
+func (m BreakModel) Project() string {
+	return m.project
+}
+
+// WithTags overrides the tags sessions and scribbles are labelled with.
+func (m BreakModel) WithTags(tags []string) BreakModel {
+	m.tags = tags
+	return m
+}
+
+// WithTimer sets how long phases last and whether they start by themselves.
+// A phase that has already started keeps its length, the new lengths apply
+// from the next one.
+func (m BreakModel) WithTimer(t config.Timer) BreakModel {
+	started := m.Timer.Timeout != m.phaseLength()
+	m.workTime = t.Focus.Duration
+	m.breakTime = t.Break.Duration
+	m.longBreak = t.LongBreak.Duration
+	m.longBreakEvery = t.LongBreakEvery
+	m.autoStart = t.AutoStart
+	if !started {
+		m.Timer.Timeout = m.phaseLength()
+	}
+	return m
+}
+
+// WithProfile sets the name of the timer profile phases are recorded with.
+// The profile's settings are applied with WithTimer and WithNotifications.
+func (m BreakModel) WithProfile(name string) BreakModel {
+	m.profile = name
+	return m
+}
+
+// WithNotifications sets the desktop notifications sent when a phase ends.
+func (m BreakModel) WithNotifications(n config.Notifications) BreakModel {
+	m.notifications = n
+	return m
+}
+
+// WithTheme restyles the break manager in t's colours.
+func (m BreakModel) WithTheme(t theme.Theme) BreakModel {
+	m.theme = t
+	m.styles = NewStyles(m.lg, t)
+	m.help.Styles = t.Help()
+	if m.showSidebar {
+		m.sidebar.SetContent(historyView(m.logger.Entries(), m.sidebar.Width, m.styles))
+	}
+	return m
+}
+
+// WithDisplay sets how the time left and the phase's progress are shown.
+func (m BreakModel) WithDisplay(d config.Display) BreakModel {
+	m.clock = d.Clock
+	m.progress = d.Progress
+	return m
+}
+
+// WithKeys rebinds the break manager's actions to the keys from the config's
+// [keys.break] table.
+func (m BreakModel) WithKeys(keys map[string][]string) BreakModel {
+	m.keymap = newKeymap(keys)
+	m.keymap.stop.SetEnabled(m.Timer.Running())
+	m.keymap.start.SetEnabled(!m.Timer.Running())
+	m.phaseKeys()
+	return m
+}
+
+// WithBreaks sets whether breaks get a screen of their own, what it suggests
+// doing and the routine it guides through first.
+func (m BreakModel) WithBreaks(b config.Breaks, routine config.Routine) BreakModel {
+	m.fullScreen = b.FullScreen
+	m.suggestions = b.Suggestions
+	m.rotate = b.Rotate.Duration
+	m.routine = routine
+	m.phaseKeys()
+	return m
+}
+
+// notify sends the desktop notification for the phase that just ended.
+func (m *BreakModel) notify() {
+	if !m.notifications.Enabled {
+		return
+	}
+	icon := ""
+	title, message := m.notifications.FocusTitle, m.notifications.FocusMessage
+	if m.state == Relaxing {
+		title, message = m.notifications.BreakTitle, m.notifications.BreakMessage
+	}
+	if err := beeep.Alert(title, message, icon); err != nil {
+		m.err = err
+	}
+}
+
 func (m BreakModel) Start() {
 	p := tea.NewProgram(m)
 	if _, err := p.Run(); err != nil {
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Widest the progress bar gets on the break screen
+const breakBarWidth = 40
+
+// breakScreen tells whether breaks have the screen to themselves and one is
+// under way.
+func (m BreakModel) breakScreen() bool {
+	return m.state == Relaxing && m.fullScreen
+}
+
+// busy tells whether a form or prompt is open, which the break screen
+// makes way for.
+func (m BreakModel) busy() bool {
+	return m.scribbling || m.picking || m.prompt != nil || m.triage != nil
+}
+
+// breakElapsed is how much of the break has gone by.
+func (m BreakModel) breakElapsed() time.Duration {
+	return max(m.breakLength()-m.Timer.Timeout, 0)
+}
+
+// routineStep finds the step of the routine the break is at and how long is
+// left of it, false when there is no routine or it is over.
+func (m BreakModel) routineStep() (int, time.Duration, bool) {
+	at := m.breakElapsed() + m.skipped
+	for i, length := range m.routine.Schedule(m.breakLength()) {
+		if at < length {
+			return i, length - at, true
+		}
+		at -= length
+	}
+	return 0, 0, false
+}
+
+// suggestion picks what to suggest, a different one every rotate and at the
+// start of every break.
+func (m BreakModel) suggestion() string {
+	if len(m.suggestions) == 0 {
+		return ""
+	}
+	i := int(m.count) + m.skips
+	if m.rotate > 0 {
+		i += int(m.breakElapsed() / m.rotate)
+	}
+	return m.suggestions[i%len(m.suggestions)]
+}
+
+// activity is what to do with the break right now, the step of the routine
+// or once it is over a suggestion, along with a label for it.
+func (m BreakModel) activity() (label, text string) {
+	if i, left, ok := m.routineStep(); ok {
+		label = fmt.Sprintf("Step %d of %d, %s left", i+1, len(m.routine.Steps), formatClock(left))
+		return label, m.routine.Steps[i].Text
+	}
+	if s := m.suggestion(); s != "" {
+		return "Try this", s
+	}
+	return "", ""
+}
+
+// skipActivity moves on to the next step of the routine, or the next
+// suggestion once it is over.
+func (m *BreakModel) skipActivity() {
+	if _, left, ok := m.routineStep(); ok {
+		m.skipped += left
+		return
+	}
+	m.skips++
+}
+
+// backEarly cuts the break short and starts focusing, recording how long the
+// break lasted.
+func (m *BreakModel) backEarly() tea.Cmd {
+	entry := breaklog.NewPhaseEntry(breaklog.BreakPhase, m.breakElapsed().Round(time.Second))
+	entry.EndedEarly = true
+	m.record(entry)
+	m.count++
+	m.switchToWork()
+	return m.Timer.Start()
+}
+
+// breakView gives the break the whole screen: the time left, how much of it
+// has gone by and something to do with it. Unless roomy is set the clock and
+// progress keep to a line each.
+func (m BreakModel) breakView(roomy bool) string {
+	width := m.contentWidth()
+	lines := []string{m.styles.HeaderText.Render(m.sessionTitle()), "", m.clockView(width, roomy)}
+	switch {
+	case m.progress == "cup" && roomy:
+		lines = append(lines, "", m.cupView())
+	case m.progress != "none":
+		lines = append(lines, "", m.progressBar(min(width, breakBarWidth)))
+	}
+	if label, text := m.activity(); text != "" {
+		text = m.styles.Highlight.Copy().Width(min(lipgloss.Width(text), width)).Align(lipgloss.Center).Render(text)
+		lines = append(lines, "", m.styles.Help.Render(label), text)
+	}
+	if m.err != nil {
+		lines = append(lines, "", m.styles.Error.Render(m.err.Error()))
+	}
+	lines = append(lines, m.helpView())
+	return lipgloss.JoinVertical(lipgloss.Center, lines...)
+}
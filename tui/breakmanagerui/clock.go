@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// glyphs are the big digits, five rows each, drawn with every cell doubled
+// so they come out about square.
+var glyphs = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {" █ ", "██ ", " █ ", " █ ", "███"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {" ", "█", " ", "█", " "},
+}
+
+// clockText shows the time left like 24:59, or 1:02:03 from an hour on.
+func (m BreakModel) clockText() string {
+	return formatClock(m.Timer.Timeout)
+}
+
+// formatClock writes d like a clock counting down would show it. It rounds
+// up to the second, so 25:00 shows until a whole second has gone by.
+func formatClock(d time.Duration) string {
+	left := (d + time.Second - 1).Truncate(time.Second)
+	h, mins, s := int(left.Hours()), int(left.Minutes())%60, int(left.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, mins, s)
+	}
+	return fmt.Sprintf("%02d:%02d", mins, s)
+}
+
+// bigClock draws text, made of digits and colons, in the big digits.
+func bigClock(text string) string {
+	var rows [5]strings.Builder
+	for i, r := range text {
+		g, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+		for row := range rows {
+			if i > 0 {
+				rows[row].WriteString(" ")
+			}
+			for _, cell := range g[row] {
+				rows[row].WriteString(strings.Repeat(string(cell), 2))
+			}
+		}
+	}
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = rows[i].String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clockView shows the time left in the big digits when they are asked for
+// and fit in width, in a line of text otherwise.
+func (m BreakModel) clockView(width int, big bool) string {
+	if big && m.clock == "big" {
+		if c := bigClock(m.clockText()); lipgloss.Width(c) <= width {
+			return m.styles.Highlight.Render(c)
+		}
+	}
+	return m.styles.Highlight.Render(m.clockText())
+}
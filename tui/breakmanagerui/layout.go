@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// Widest the status box gets
+	boxWidth = 48
+	// Widest the view gets on a large terminal, room for the box and the
+	// sidebar side by side
+	largeWidth = 100
+)
+
+// resize lays the view out for a width by height terminal.
+func (m *BreakModel) resize(width, height int) {
+	m.width, m.height = width, height
+	m.help.Width = m.contentWidth()
+	m.sidebar.Width = m.sidebarWidth()
+	for _, f := range m.forms() {
+		f.WithWidth(m.formWidth())
+	}
+}
+
+func (m BreakModel) size() layout.Size {
+	return layout.Of(m.width, m.height)
+}
+
+// contentWidth is the width of everything inside the outer padding.
+func (m BreakModel) contentWidth() int {
+	if m.size() == layout.Compact {
+		return m.width
+	}
+	widest := maxWidth
+	if m.size() == layout.Large {
+		widest = largeWidth
+	}
+	return max(min(m.width-m.styles.Base.GetHorizontalFrameSize(), widest), 20)
+}
+
+// statusWidth is the width of the status box, border and margins left out.
+func (m BreakModel) statusWidth() int {
+	return min(boxWidth, m.contentWidth()-4)
+}
+
+// formWidth is the width forms are drawn at, under the status box.
+func (m BreakModel) formWidth() int {
+	return m.contentWidth() - 2
+}
+
+// sidebarBeside tells whether the sidebar fits next to the status box, it
+// goes under it otherwise.
+func (m BreakModel) sidebarBeside() bool {
+	return m.contentWidth() >= m.statusWidth()+4+sidebarWidth+m.styles.Sidebar.GetHorizontalFrameSize()
+}
+
+func (m BreakModel) sidebarWidth() int {
+	if m.size() == layout.Compact {
+		return m.width
+	}
+	return min(sidebarWidth, m.contentWidth()-m.styles.Sidebar.GetHorizontalFrameSize())
+}
+
+// forms returns the forms open at the moment.
+func (m BreakModel) forms() []*huh.Form {
+	var forms []*huh.Form
+	if m.scribble != nil {
+		forms = append(forms, m.scribble.form)
+	}
+	if m.triage != nil {
+		forms = append(forms, m.triage.form)
+	}
+	if m.taskPicker != nil {
+		forms = append(forms, m.taskPicker.form)
+	}
+	return forms
+}
+
+// styleForm themes and sizes a form about to be shown.
+func (m BreakModel) styleForm(f *huh.Form) {
+	f.WithTheme(m.theme.Form()).WithWidth(m.formWidth())
+}
+
+// sessionTitle names the phase and counts the sessions, like "Work Session:
+// 2".
+func (m BreakModel) sessionTitle() string {
+	switch m.state {
+	case Focusing:
+		return fmt.Sprintf("Work Session: %v", m.count)
+	case Relaxing:
+		if m.breakLength() != m.breakTime {
+			return fmt.Sprintf("Long Break Session: %v", m.count)
+		}
+		return fmt.Sprintf("Break Session: %v", m.count)
+	}
+	return ""
+}
+
+// compactView keeps to the session, the time left and the task, for panes
+// too small for the full view. Whatever is open takes the rest of the room.
+func (m BreakModel) compactView() string {
+	lines := []string{m.sessionTitle() + " " + m.styles.Highlight.Render(m.clockText())}
+	if m.progress != "none" {
+		// A cup doesn't fit, the bar stands in for it.
+		lines = append(lines, m.progressBar(m.width))
+	}
+	switch {
+	case m.scribbling:
+		lines = append(lines, m.scribble.form.View())
+	case m.prompt != nil:
+		lines = append(lines, m.styles.StatusHeader.Render(m.prompt.title), m.prompt.input.View())
+	case m.triage != nil:
+		lines = append(lines, m.triage.form.View())
+	case m.picking:
+		lines = append(lines, m.taskPicker.form.View())
+	default:
+		if _, text := m.activity(); m.state == Relaxing && text != "" {
+			lines = append(lines, text)
+		} else if m.task != "" {
+			lines = append(lines, m.styles.StatusHeader.Render("Task: ")+m.task)
+		}
+		if m.err != nil {
+			lines = append(lines, m.styles.Error.Render(m.err.Error()))
+		}
+		help := m.helpView()
+		if m.showSidebar {
+			// The history gets what the rest leaves over.
+			sidebar := m.sidebar
+			sidebar.Height = max(m.height-len(lines)-lipgloss.Height(help)-1, 1)
+			sidebar.SetContent(historyView(m.logger.Entries(), sidebar.Width, m.styles))
+			lines = append(lines, sidebar.View())
+		}
+		lines = append(lines, help)
+	}
+	return lipgloss.NewStyle().
+		MaxWidth(m.width).
+		MaxHeight(m.height).
+		Render(strings.Join(lines, "\n"))
+}
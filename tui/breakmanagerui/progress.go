@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Inside of the boba cup, in cells
+const (
+	cupWidth  = 9
+	cupHeight = 5
+	// Column the straw goes down
+	strawColumn = 2
+)
+
+// elapsed is how much of the current phase has gone by, from 0 to 1.
+func (m BreakModel) elapsed() float64 {
+	length := m.phaseLength()
+	if length <= 0 {
+		return 0
+	}
+	f := 1 - float64(m.Timer.Timeout)/float64(length)
+	return min(max(f, 0), 1)
+}
+
+// phaseStyle colours what belongs to the current phase.
+func (m BreakModel) phaseStyle() lipgloss.Style {
+	if m.state == Relaxing {
+		return m.styles.Break
+	}
+	return m.styles.Focus
+}
+
+// progressBar fills up width cells, the percentage included, as the phase
+// goes by.
+func (m BreakModel) progressBar(width int) string {
+	percent := fmt.Sprintf(" %3.0f%%", m.elapsed()*100)
+	width = max(width-len(percent), 1)
+	filled := int(math.Round(m.elapsed() * float64(width)))
+	return m.phaseStyle().Render(strings.Repeat("█", filled)) +
+		m.styles.Help.Render(strings.Repeat("░", width-filled)) +
+		percent
+}
+
+// cupView draws the boba cup, which fills up as focus goes by and is drunk
+// during breaks. The pearls and the surface move with every tick.
+func (m BreakModel) cupView() string {
+	level := m.elapsed()
+	if m.state == Relaxing {
+		level = 1 - level
+	}
+	filled := int(math.Round(level * cupHeight))
+	frame := int(m.Timer.Timeout/tickInterval) % 2
+	wall, straw, pearl := m.styles.Help, m.styles.StatusHeader, m.styles.Highlight
+
+	lines := []string{
+		strings.Repeat(" ", strawColumn+1) + straw.Render("║"),
+		wall.Render("╭"+strings.Repeat("─", strawColumn)) + straw.Render("║") + wall.Render(strings.Repeat("─", cupWidth-strawColumn-1)+"╮"),
+	}
+	for row := 0; row < cupHeight; row++ {
+		var b strings.Builder
+		b.WriteString(wall.Render("│"))
+		for col := 0; col < cupWidth; col++ {
+			switch {
+			case col == strawColumn:
+				b.WriteString(straw.Render("║"))
+			case row == cupHeight-1 && (col+frame)%3 == 0:
+				// The pearls stay at the bottom once the tea is gone.
+				b.WriteString(pearl.Render("●"))
+			case row == cupHeight-filled:
+				wave := "~"
+				if (col+frame)%2 == 0 {
+					wave = "≈"
+				}
+				b.WriteString(m.phaseStyle().Render(wave))
+			case row > cupHeight-filled:
+				b.WriteString(m.phaseStyle().Render("▒"))
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(wall.Render("│"))
+		lines = append(lines, b.String())
+	}
+	lines = append(lines, wall.Render("╰"+strings.Repeat("─", cupWidth)+"╯"))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+type promptKind int
+
+const (
+	internalInterruptionPrompt promptKind = iota
+	externalInterruptionPrompt
+	parkPrompt
+	contextPrompt
+)
+
+// linePrompt is a single line of input shown under the timer. Unlike the
+// scribble form it doesn't pause the timer.
+type linePrompt struct {
+	kind  promptKind
+	title string
+	input textinput.Model
+}
+
+func newLinePrompt(kind promptKind, title string, placeholder string) *linePrompt {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = 200
+	input.Focus()
+	return &linePrompt{
+		kind:  kind,
+		title: title,
+		input: input,
+	}
+}
+
+// newContextPrompt asks where the user left off when a focus phase ends.
+func newContextPrompt() *linePrompt {
+	return newLinePrompt(contextPrompt, "Where did you leave off?", "what you were doing and the next step")
+}
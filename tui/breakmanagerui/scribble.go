@@ -1,29 +1,119 @@
 package breakmanagerui
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/tui/editor"
 	"github.com/charmbracelet/huh"
 )
 
+// scribbleTemplate words the scribble form for the phase it is written in.
+type scribbleTemplate struct {
+	wipTitle, wipPlaceholder           string
+	findingsTitle, findingsPlaceholder string
+	reasonTitle, reasonPlaceholder     string
+}
+
+var scribbleTemplates = map[breaklog.Phase]scribbleTemplate{
+	breaklog.FocusPhase: {
+		wipTitle:            "Work in progress",
+		wipPlaceholder:      "What are you in the middle of, and what's next?",
+		findingsTitle:       "Findings",
+		findingsPlaceholder: "Anything learned or worth remembering",
+		reasonTitle:         "Why stop now?",
+		reasonPlaceholder:   "optional",
+	},
+	breaklog.BreakPhase: {
+		wipTitle:            "Left in progress",
+		wipPlaceholder:      "What's waiting for you after the break?",
+		findingsTitle:       "Thoughts",
+		findingsPlaceholder: "Anything that came to mind while away",
+		reasonTitle:         "Why this break?",
+		reasonPlaceholder:   "optional",
+	},
+}
+
+var moods = []huh.Option[string]{
+	huh.NewOption("skip", ""),
+	huh.NewOption("energized", "energized"),
+	huh.NewOption("focused", "focused"),
+	huh.NewOption("okay", "okay"),
+	huh.NewOption("distracted", "distracted"),
+	huh.NewOption("drained", "drained"),
+}
+
 type scribble struct {
-	text   string
-	form   huh.Form
-	logger *breaklog.FileBreakLogger
+	workInProgress string
+	findings       string
+	reason         string
+	project        string
+	tags           string
+	mood           string
+	form           *huh.Form
 }
 
-func New() *scribble {
-	l, err := breaklog.NewFileBreakLogger("data/entry.json")
-	if err != nil {
-		panic(err)
+// New creates a scribble for the given phase whose project and tags start out
+// as the session's.
+func New(phase breaklog.Phase, project string, tags []string) *scribble {
+	t, ok := scribbleTemplates[phase]
+	if !ok {
+		t = scribbleTemplates[breaklog.FocusPhase]
 	}
 	s := scribble{
-		text:   "",
-		logger: l,
+		project: project,
+		tags:    strings.Join(tags, ", "),
 	}
-	s.form = *huh.NewForm(huh.NewGroup(huh.NewText().Title("Current Thoughts").CharLimit(1000).Value(&s.text)))
+	s.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title(t.wipTitle).
+				Placeholder(t.wipPlaceholder).
+				CharLimit(1000).
+				Editor(editor.Command()...).
+				Value(&s.workInProgress),
+			huh.NewText().
+				Title(t.findingsTitle).
+				Placeholder(t.findingsPlaceholder).
+				CharLimit(1000).
+				Editor(editor.Command()...).
+				Validate(func(string) error {
+					if strings.TrimSpace(s.workInProgress) == "" && strings.TrimSpace(s.findings) == "" {
+						return errors.New("write down what's in progress or what you found")
+					}
+					return nil
+				}).
+				Value(&s.findings),
+			huh.NewInput().
+				Title(t.reasonTitle).
+				Placeholder(t.reasonPlaceholder).
+				CharLimit(200).
+				Value(&s.reason),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Project").
+				CharLimit(100).
+				Value(&s.project),
+			huh.NewInput().
+				Title("Tags").
+				Placeholder("comma separated").
+				Validate(breaklog.ValidateTags).
+				Value(&s.tags),
+			huh.NewSelect[string]().
+				Title("Mood").
+				Value(&s.mood).
+				Options(moods...),
+		),
+	)
 	return &s
 }
 
-func (s scribble) log() {
-	s.logger.AddLogEntry(breaklog.NewBreakLogEntry(s.text, ""))
+// entry turns the filled in form into a break log entry.
+func (s scribble) entry() *breaklog.BreakLogEntry {
+	entry := breaklog.NewBreakLogEntry(strings.TrimSpace(s.findings), strings.TrimSpace(s.reason))
+	entry.WorkInProgress = strings.TrimSpace(s.workInProgress)
+	entry.Mood = s.mood
+	return entry
 }
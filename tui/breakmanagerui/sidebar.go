@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	sidebarWidth  = 34
+	sidebarHeight = 14
+)
+
+func newSidebar() viewport.Model {
+	return viewport.New(sidebarWidth, sidebarHeight)
+}
+
+// historyView lists today's scribbles and finished phases, newest first.
+func historyView(entries []breaklog.BreakLogEntry, width int, styles *Styles) string {
+	now := time.Now()
+	var lines []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		t := e.Timestamp.Local()
+		if t.YearDay() != now.YearDay() || t.Year() != now.Year() {
+			continue
+		}
+		var line string
+		switch e.Kind {
+		case breaklog.PhaseEntry:
+			style := styles.Focus
+			if e.Phase == breaklog.BreakPhase {
+				style = styles.Break
+			}
+			done := "done"
+			if e.EndedEarly {
+				done = "ended early"
+			}
+			line = style.Render(fmt.Sprintf("%s %s, %v", e.Phase, done, e.Duration))
+			if e.Task != "" {
+				line += " · " + e.Task
+			}
+			if e.WorkInProgress != "" {
+				line += "\n" + e.WorkInProgress
+			}
+		case breaklog.InterruptionEntry:
+			continue
+		default:
+			var parts []string
+			for _, text := range []string{e.WorkInProgress, e.Findings, e.Reason} {
+				if text != "" {
+					parts = append(parts, text)
+				}
+			}
+			line = strings.Join(parts, "\n")
+		}
+		lines = append(lines, styles.SidebarTime.Render(t.Format("15:04"))+" "+line)
+	}
+	if len(lines) == 0 {
+		return styles.SidebarTime.Render("Nothing noted yet today.")
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n\n"))
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+
+	"github.com/SamD2021/boba-break/internal/task"
+	"github.com/charmbracelet/huh"
+)
+
+// taskPicker is the select shown while choosing which task the focus
+// sessions count towards.
+type taskPicker struct {
+	selected string
+	form     *huh.Form
+}
+
+func newTaskPicker(tasks []task.Task, current string) *taskPicker {
+	p := taskPicker{selected: current}
+	options := []huh.Option[string]{huh.NewOption("(no task)", "")}
+	for _, t := range tasks {
+		label := t.Name
+		if t.Estimate > 0 {
+			label = fmt.Sprintf("%s (est. %d)", t.Name, t.Estimate)
+		}
+		options = append(options, huh.NewOption(label, t.Name))
+	}
+	p.form = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Active Task").
+			Value(&p.selected).
+			Options(options...),
+	))
+	return &p
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package breakmanagerui
+
+import (
+	"fmt"
+
+	"github.com/SamD2021/boba-break/internal/parkinglot"
+	"github.com/charmbracelet/huh"
+)
+
+type triageAction int
+
+// parkedNote collects the parked thoughts kept as notes.
+const parkedNote = "Parked thoughts"
+
+const (
+	triageLater triageAction = iota
+	triageTask
+	triageNote
+	triageDiscard
+)
+
+// triage walks through the parking lot one item at a time at the start of a
+// break, asking what should become of each.
+type triage struct {
+	items  []parkinglot.Item
+	action triageAction
+	form   *huh.Form
+	theme  *huh.Theme
+}
+
+func newTriage(items []parkinglot.Item, theme *huh.Theme) *triage {
+	t := triage{items: items, theme: theme}
+	t.newForm()
+	return &t
+}
+
+func (t *triage) current() parkinglot.Item {
+	return t.items[0]
+}
+
+// advance moves on to the next item, reporting false once none are left.
+func (t *triage) advance() bool {
+	t.items = t.items[1:]
+	if len(t.items) == 0 {
+		return false
+	}
+	t.newForm()
+	return true
+}
+
+func (t *triage) newForm() {
+	t.action = triageTask
+	t.form = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[triageAction]().
+			Title(fmt.Sprintf("Parking lot (%d left)", len(t.items))).
+			Description(t.current().Text).
+			Value(&t.action).
+			Options(
+				huh.NewOption("Make it a task", triageTask),
+				huh.NewOption("Add it to the "+parkedNote+" note", triageNote),
+				huh.NewOption("Discard", triageDiscard),
+				huh.NewOption("Leave it for later", triageLater),
+			),
+	)).WithTheme(t.theme)
+}
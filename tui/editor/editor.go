@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fallback is used when neither $VISUAL nor $EDITOR is set.
+const fallback = "vi"
+
+// FinishedMsg carries what was left in the editor when it exited.
+type FinishedMsg struct {
+	Content string
+	Err     error
+}
+
+// Command returns the user's editor and its arguments, preferring $VISUAL
+// over $EDITOR.
+func Command() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{fallback}
+}
+
+// Open suspends the program, edits content in the user's editor through a
+// temporary Markdown file and sends a FinishedMsg once the editor exits. The
+// program's other messages, timer ticks included, wait until then.
+func Open(content string) tea.Cmd {
+	f, err := os.CreateTemp("", "boba-break-*.md")
+	if err != nil {
+		return failed(err)
+	}
+	_, err = f.WriteString(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return failed(err)
+	}
+
+	args := Command()
+	c := exec.Command(args[0], append(args[1:], f.Name())...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		defer os.Remove(f.Name())
+		if err != nil {
+			return FinishedMsg{Err: err}
+		}
+		b, err := os.ReadFile(f.Name())
+		return FinishedMsg{Content: string(b), Err: err}
+	})
+}
+
+func failed(err error) tea.Cmd {
+	return func() tea.Msg {
+		return FinishedMsg{Err: err}
+	}
+}
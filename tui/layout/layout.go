@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+// Package layout sorts terminal sizes into the few layouts the views have.
+package layout
+
+// Size is how much room a view has.
+type Size int
+
+const (
+	// Compact is a small pane, like a 30x8 tmux split. Views keep to what
+	// matters, without borders or margins.
+	Compact Size = iota
+	// Regular is a terminal of the usual size.
+	Regular
+	// Large is a full screen terminal, where views are centred.
+	Large
+)
+
+// Below these a pane is compact.
+const (
+	CompactWidth  = 50
+	CompactHeight = 16
+)
+
+// From these on a terminal is large.
+const (
+	LargeWidth  = 110
+	LargeHeight = 32
+)
+
+// Of tells the size of a width by height terminal. Before the terminal
+// reports its size the view doesn't know one of them, which is taken as
+// regular.
+func Of(width, height int) Size {
+	switch {
+	case width == 0 || height == 0:
+		return Regular
+	case width < CompactWidth || height < CompactHeight:
+		return Compact
+	case width >= LargeWidth && height >= LargeHeight:
+		return Large
+	default:
+		return Regular
+	}
+}
@@ -19,13 +19,22 @@
 package mainmenuui
 
 import (
+	"github.com/SamD2021/boba-break/tui/bindings"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func newItemDelegate(keys *delegateKeyMap) list.DefaultDelegate {
+func newItemDelegate(keys *delegateKeyMap, t theme.Theme, size layout.Size) list.DefaultDelegate {
 	d := list.NewDefaultDelegate()
+	d.Styles = t.Items()
+	if size == layout.Compact {
+		// One line per item, so a small pane still shows a few.
+		d.ShowDescription = false
+		d.SetSpacing(0)
+	}
 
 	d.UpdateFunc = func(msg tea.Msg, m *list.Model) tea.Cmd {
 		var title string
@@ -40,6 +49,11 @@ func newItemDelegate(keys *delegateKeyMap) list.DefaultDelegate {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, keys.choose):
+				if i, ok := m.SelectedItem().(item); ok && i.profile != "" {
+					return func() tea.Msg {
+						return SelectedProfileMsg{Name: i.profile}
+					}
+				}
 				switch title {
 				case "Break":
 					return func() tea.Msg {
@@ -49,8 +63,16 @@ func newItemDelegate(keys *delegateKeyMap) list.DefaultDelegate {
 					return func() tea.Msg {
 						return SelectedNoteMsg{}
 					}
+				case "Search":
+					return func() tea.Msg {
+						return SelectedSearchMsg{}
+					}
+				case "Settings":
+					return func() tea.Msg {
+						return SelectedSettingsMsg{}
+					}
 				}
-				return m.NewStatusMessage(statusMessageStyle("You chose " + title))
+				return m.NewStatusMessage(statusMessageStyle(t).Render("You chose " + title))
 
 				// case key.Matches(msg, keys.remove):
 				// 	index := m.Index()
@@ -65,14 +87,14 @@ func newItemDelegate(keys *delegateKeyMap) list.DefaultDelegate {
 		return nil
 	}
 
-	help := []key.Binding{keys.choose}
-
+	// The keys can be rebound after the delegate is made, the help is
+	// built from them each time.
 	d.ShortHelpFunc = func() []key.Binding {
-		return help
+		return []key.Binding{keys.choose}
 	}
 
 	d.FullHelpFunc = func() [][]key.Binding {
-		return [][]key.Binding{help}
+		return [][]key.Binding{{keys.choose}}
 	}
 
 	return d
@@ -103,12 +125,9 @@ func (d delegateKeyMap) FullHelp() [][]key.Binding {
 	}
 }
 
-func newDelegateKeyMap() *delegateKeyMap {
+func newDelegateKeyMap(keys map[string][]string) *delegateKeyMap {
 	return &delegateKeyMap{
-		choose: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "choose"),
-		),
+		choose: bindings.New(keys["choose"], "choose"),
 		// remove: key.NewBinding(
 		// 	key.WithKeys("x", "backspace"),
 		// 	key.WithHelp("x", "delete"),
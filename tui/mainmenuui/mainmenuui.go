@@ -22,33 +22,39 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/tui/bindings"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	appStyle = lipgloss.NewStyle().Padding(1, 2)
+var appStyle = lipgloss.NewStyle().Padding(1, 2)
 
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFDF5")).
-			Background(lipgloss.Color("#25A065")).
-			Padding(0, 1)
+// Widest the menu gets on a large terminal, it is centred in the rest
+const largeWidth = 72
 
-	statusMessageStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.AdaptiveColor{Light: "#04B575", Dark: "#04B575"}).
-				Render
-)
+// statusMessageStyle is the style of status messages in t's colours.
+func statusMessageStyle(t theme.Theme) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.Accent)
+}
 
 type Model struct {
 	list         list.Model
 	keys         *listKeyMap
 	delegateKeys *delegateKeyMap
+	theme        theme.Theme
+	// Size of the terminal, 0 until it is reported
+	width, height int
 }
 
 type item struct {
 	title, desc string
+	// Timer profile the item starts, for quick start items
+	profile string
 }
 
 func (i item) Title() string       { return i.title }
@@ -61,9 +67,31 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) View() string {
+	switch layout.Of(m.width, m.height) {
+	case layout.Compact:
+		return lipgloss.NewStyle().MaxHeight(m.height).Render(m.list.View())
+	case layout.Large:
+		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, appStyle.Render(m.list.View()))
+	}
 	return appStyle.Render(m.list.View())
 }
 
+// resize fits the menu to a width by height terminal. Compact panes drop the
+// padding and the item descriptions.
+func (m *Model) resize(width, height int) {
+	m.width, m.height = width, height
+	size := layout.Of(width, height)
+	if size != layout.Compact {
+		h, v := appStyle.GetFrameSize()
+		width, height = width-h, height-v
+	}
+	if size == layout.Large {
+		width = min(width, largeWidth)
+	}
+	m.list.SetSize(width, height)
+	m.list.SetDelegate(newItemDelegate(m.delegateKeys, m.theme, size))
+}
+
 func Start() {
 	p := tea.NewProgram(NewModel())
 	if _, err := p.Run(); err != nil {
@@ -83,39 +111,29 @@ type listKeyMap struct {
 	// insertItem       key.Binding
 }
 
-func newListKeyMap() *listKeyMap {
+func newListKeyMap(keys map[string][]string) *listKeyMap {
 	return &listKeyMap{
-		// insertItem: key.NewBinding(
-		// 	key.WithKeys("a"),
-		// 	key.WithHelp("a", "add item"),
-		// ),
-		toggleSpinner: key.NewBinding(
-			key.WithKeys("s"),
-			key.WithHelp("s", "toggle spinner"),
-		),
-		toggleTitleBar: key.NewBinding(
-			key.WithKeys("T"),
-			key.WithHelp("T", "toggle title"),
-		),
-		toggleStatusBar: key.NewBinding(
-			key.WithKeys("S"),
-			key.WithHelp("S", "toggle status"),
-		),
-		togglePagination: key.NewBinding(
-			key.WithKeys("P"),
-			key.WithHelp("P", "toggle pagination"),
-		),
-		toggleHelpMenu: key.NewBinding(
-			key.WithKeys("H"),
-			key.WithHelp("H", "toggle help"),
-		),
+		toggleSpinner:    bindings.New(keys["spinner"], "toggle spinner"),
+		toggleTitleBar:   bindings.New(keys["title"], "toggle title"),
+		toggleStatusBar:  bindings.New(keys["status"], "toggle status"),
+		togglePagination: bindings.New(keys["pagination"], "toggle pagination"),
+		toggleHelpMenu:   bindings.New(keys["help"], "toggle help"),
 	}
 }
 
+// WithKeys rebinds the menu's actions to the keys from the config's
+// [keys.menu] table.
+func (m Model) WithKeys(keys map[string][]string) Model {
+	*m.keys = *newListKeyMap(keys)
+	*m.delegateKeys = *newDelegateKeyMap(keys)
+	return m
+}
+
 func NewModel() Model {
 	var (
-		delegateKeys = newDelegateKeyMap()
-		listKeys     = newListKeyMap()
+		keys         = config.Default().KeysOf("menu")
+		delegateKeys = newDelegateKeyMap(keys)
+		listKeys     = newListKeyMap(keys)
 	)
 
 	// Make initial list of items
@@ -123,13 +141,17 @@ func NewModel() Model {
 	items := []list.Item{
 		item{title: "Break"},
 		item{title: "Notes"},
+		item{title: "Search"},
+		item{title: "Settings"},
 	}
 
 	// Setup list
-	delegate := newItemDelegate(delegateKeys)
+	t := theme.Default()
+	delegate := newItemDelegate(delegateKeys, t, layout.Regular)
 	menuList := list.New(items, delegate, 0, 0)
 	menuList.Title = "Menu"
-	menuList.Styles.Title = titleStyle
+	menuList.Styles = t.List()
+	menuList.Help.Styles = t.Help()
 	menuList.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			listKeys.toggleSpinner,
@@ -145,16 +167,41 @@ func NewModel() Model {
 		list:         menuList,
 		keys:         listKeys,
 		delegateKeys: delegateKeys,
+		theme:        t,
 	}
 }
 
+// WithTheme styles the menu in t's colours.
+func (m Model) WithTheme(t theme.Theme) Model {
+	m.theme = t
+	m.list.SetDelegate(newItemDelegate(m.delegateKeys, t, layout.Of(m.width, m.height)))
+	m.list.Styles = t.List()
+	m.list.Help.Styles = t.Help()
+	return m
+}
+
+// WithProfiles adds a quick start item under Break for each timer profile in
+// cfg.
+func (m Model) WithProfiles(cfg config.Config) Model {
+	for i, name := range cfg.ProfileNames() {
+		p, err := cfg.WithProfile(name)
+		if err != nil {
+			continue
+		}
+		m.list.InsertItem(1+i, item{title: "Start " + name, desc: p.Timer.Summary(), profile: name})
+	}
+	return m
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		h, v := appStyle.GetFrameSize()
-		m.list.SetSize(msg.Width-h, msg.Height-v)
+		m.resize(msg.Width, msg.Height)
+
+	case StatusMsg:
+		return m, m.list.NewStatusMessage(statusMessageStyle(m.theme).Render(string(msg)))
 
 	case tea.KeyMsg:
 		// Don't match any of the keys below if we're actively filtering.
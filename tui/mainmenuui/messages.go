@@ -21,4 +21,12 @@ package mainmenuui
 type (
 	SelectedBreakManagerMsg struct{}
 	SelectedNoteMsg         struct{}
+	SelectedSearchMsg       struct{}
+	SelectedSettingsMsg     struct{}
+	// SelectedProfileMsg starts the break manager with a timer profile.
+	SelectedProfileMsg struct {
+		Name string
+	}
+	// StatusMsg shows a short message under the menu.
+	StatusMsg string
 )
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package noteui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/task"
+)
+
+// toggleCheckbox checks or unchecks the checklist item under the cursor,
+// leaving the cursor where it was.
+func (m *NotesModel) toggleCheckbox() {
+	row := m.textarea.Line()
+	li := m.textarea.LineInfo()
+	col := li.StartColumn + li.ColumnOffset
+	lines := strings.Split(m.textarea.Value(), "\n")
+	line, ok := notes.ToggleCheckbox(lines[row])
+	if !ok {
+		return
+	}
+	lines[row] = line
+	m.textarea.SetValue(strings.Join(lines, "\n"))
+	m.moveToLine(row)
+	m.textarea.SetCursor(col)
+}
+
+// moveToLine puts the cursor at the start of a line of the note.
+func (m *NotesModel) moveToLine(row int) {
+	m.textarea.CursorEnd()
+	for m.textarea.Line() > row {
+		m.textarea.CursorUp()
+	}
+	for m.textarea.Line() < row {
+		m.textarea.CursorDown()
+	}
+	m.textarea.CursorStart()
+}
+
+// promoteUnchecked adds a task for every unchecked item of the note that
+// isn't a task already.
+func (m *NotesModel) promoteUnchecked() {
+	store, err := task.NewFileTaskStore(task.DefaultFilePath)
+	if err != nil {
+		m.err = err
+		return
+	}
+	added := 0
+	for _, item := range notes.Unchecked(m.textarea.Value()) {
+		if _, err := store.Find(item); err == nil {
+			continue
+		}
+		if _, err := store.Add(item, 0); err != nil {
+			m.err = err
+			return
+		}
+		added++
+	}
+	switch added {
+	case 0:
+		m.notice = "no new tasks"
+	case 1:
+		m.notice = "added 1 task"
+	default:
+		m.notice = fmt.Sprintf("added %d tasks", added)
+	}
+}
+
+// checklistView shows how much of the note's checklist is done.
+func (m NotesModel) checklistView() string {
+	done, total := notes.Checklist(m.textarea.Value())
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %d/%d done", done, total)
+}
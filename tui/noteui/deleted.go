@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package noteui
+
+import (
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// deletedPicker asks which deleted note to bring back from its history.
+type deletedPicker struct {
+	selected string
+	form     *huh.Form
+}
+
+func newDeletedPicker(deleted []notes.Note) *deletedPicker {
+	p := deletedPicker{}
+	options := make([]huh.Option[string], len(deleted))
+	for i, n := range deleted {
+		options[i] = huh.NewOption(n.Name+" (deleted "+n.ModTime.Format("Mon Jan 2 15:04")+")", n.Name)
+	}
+	p.form = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Bring back a deleted note").
+			Value(&p.selected).
+			Options(options...),
+	))
+	return &p
+}
+
+// pickDeleted lists the deleted notes to bring one back.
+func (m *NotesModel) pickDeleted() tea.Cmd {
+	deleted, err := m.store.Deleted()
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	if len(deleted) == 0 {
+		return m.list.NewStatusMessage("no deleted notes")
+	}
+	m.deleted = newDeletedPicker(deleted)
+	m.deleted.form.WithTheme(m.theme.Form())
+	return m.deleted.form.Init()
+}
+
+func (m NotesModel) updateDeleted(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
+		m.deleted = nil
+		return m, nil
+	}
+	form, cmd := m.deleted.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.deleted.form = f
+	}
+	switch m.deleted.form.State {
+	case huh.StateCompleted:
+		name := m.deleted.selected
+		m.deleted = nil
+		if err := m.store.Undelete(name); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.openNote(name)
+		m.refreshList()
+		m.notice = "brought back as it was when deleted"
+		return m, textarea.Blink
+	case huh.StateAborted:
+		m.deleted = nil
+	}
+	return m, cmd
+}
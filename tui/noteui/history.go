@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package noteui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const snapshotListWidth = 20
+
+var snapshotStyle = lipgloss.NewStyle().Width(snapshotListWidth)
+
+// history browses the snapshots of the open note, showing how the selected
+// one differs from what the note holds now.
+type history struct {
+	snapshots []notes.Snapshot
+	cursor    int
+	diff      viewport.Model
+}
+
+// openHistory switches to the history of the open note.
+func (m *NotesModel) openHistory() {
+	snaps, err := m.store.History(m.current)
+	if err != nil {
+		m.err = err
+		return
+	}
+	if len(snaps) == 0 {
+		m.notice = "no earlier versions yet"
+		return
+	}
+	m.history = &history{snapshots: snaps, diff: viewport.New(0, 0)}
+	m.state = browsingHistory
+	m.textarea.Blur()
+	m.layoutHistory()
+}
+
+func (m *NotesModel) layoutHistory() {
+	if m.history == nil {
+		return
+	}
+	m.history.diff.Width = m.width - snapshotListWidth - 2
+	chrome := chromeHeight
+	if m.size() == layout.Compact {
+		chrome = compactChromeHeight
+	}
+	m.history.diff.Height = max(m.height-chrome, 3)
+	m.renderDiff()
+}
+
+// renderDiff shows what changed between the selected snapshot and the note.
+func (m *NotesModel) renderDiff() {
+	h := m.history
+	old, err := m.store.ReadSnapshot(h.snapshots[h.cursor])
+	if err != nil {
+		m.err = err
+		return
+	}
+	var b strings.Builder
+	for _, l := range notes.Diff(old, m.textarea.Value()) {
+		line := string(l.Op) + " " + l.Text
+		switch l.Op {
+		case notes.DiffRemoved:
+			line = m.styles.Removed.Render(line)
+		case notes.DiffAdded:
+			line = m.styles.Added.Render(line)
+		default:
+			line = m.styles.Muted.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	h.diff.SetContent(strings.TrimSuffix(b.String(), "\n"))
+	h.diff.GotoTop()
+}
+
+func (m NotesModel) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	h := m.history
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case msg.Type == tea.KeyCtrlC:
+			return m, tea.Quit
+		case key.Matches(msg, m.keymap.closeHistory):
+			m.history = nil
+			m.state = editing
+			return m, m.textarea.Focus()
+		case key.Matches(msg, m.keymap.older):
+			if h.cursor < len(h.snapshots)-1 {
+				h.cursor++
+				m.renderDiff()
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.newer):
+			if h.cursor > 0 {
+				h.cursor--
+				m.renderDiff()
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.restore):
+			snap := h.snapshots[h.cursor]
+			if err := m.store.Restore(snap); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.history = nil
+			m.openNote(m.current)
+			m.renderPreview()
+			m.notice = "restored the version from " + snap.Time.Format("Mon Jan 2 15:04")
+			return m, textarea.Blink
+		}
+	}
+	var cmd tea.Cmd
+	h.diff, cmd = h.diff.Update(msg)
+	return m, cmd
+}
+
+func (m NotesModel) historyView() string {
+	h := m.history
+	var list strings.Builder
+	for i, s := range h.snapshots {
+		label := s.Time.Format("Jan 2 15:04:05")
+		if i == h.cursor {
+			label = m.styles.Selected.Render("> " + label)
+		} else {
+			label = "  " + label
+		}
+		list.WriteString(label + "\n")
+	}
+	snap := h.snapshots[h.cursor]
+	return fmt.Sprintf(
+		"%s %s\n\n%s\n%s\n%s",
+		noteTitleStyle.Render(m.current),
+		m.styles.Muted.Render("changes since "+snap.Time.Format("Mon Jan 2 15:04")),
+		lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			snapshotStyle.Render(list.String()),
+			previewStyle.Copy().BorderForeground(m.theme.Muted).Render(h.diff.View()),
+		),
+		m.errView(),
+		"\n"+m.help.ShortHelpView([]key.Binding{
+			m.keymap.newer,
+			m.keymap.older,
+			m.keymap.restore,
+			m.keymap.closeHistory,
+		}),
+	) + "\n\n"
+}
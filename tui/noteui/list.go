@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package noteui
+
+import (
+	"github.com/SamD2021/boba-break/internal/journal"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type item struct {
+	note notes.Note
+}
+
+func (i item) Title() string       { return i.note.Name }
+func (i item) FilterValue() string { return i.note.Name }
+
+func (i item) Description() string {
+	if _, ok := journal.Day(i.note.Name); ok {
+		return "journal, updated " + i.note.ModTime.Format("Mon Jan 2 15:04")
+	}
+	return "edited " + i.note.ModTime.Format("Mon Jan 2 15:04")
+}
+
+func newNoteList(keys keymap) list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 60, 20)
+	l.Title = "Notes"
+	l.SetStatusBarItemName("note", "notes")
+	// The notes view decides what quits the app, not the list.
+	l.DisableQuitKeybindings()
+	l.AdditionalShortHelpKeys = keys.listHelp
+	return l
+}
+
+func noteDelegate(t theme.Theme, size layout.Size) list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	d.Styles = t.Items()
+	if size == layout.Compact {
+		// One line per note, so a small pane still shows a few.
+		d.ShowDescription = false
+		d.SetSpacing(0)
+	}
+	return d
+}
+
+func noteItems(ns []notes.Note) []list.Item {
+	items := make([]list.Item, len(ns))
+	for i, n := range ns {
+		items[i] = item{note: n}
+	}
+	return items
+}
@@ -19,3 +19,10 @@
 package noteui
 
 type GoBackMsg struct{}
+
+// OpenMsg opens a note in the editor, with the cursor on the first line
+// matching Query if there is one.
+type OpenMsg struct {
+	Name  string
+	Query string
+}
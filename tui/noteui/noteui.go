@@ -2,69 +2,368 @@ package noteui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/internal/journal"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/notetemplate"
+	"github.com/SamD2021/boba-break/internal/search"
+	"github.com/SamD2021/boba-break/tui/bindings"
+	"github.com/SamD2021/boba-break/tui/editor"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/huh"
 	// "github.com/charmbracelet/logs"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
+// untitled is the note written to when notes are opened for the first time.
+const untitled = "Untitled"
+
+var noteTitleStyle = lipgloss.NewStyle().Bold(true)
+
+// saveDelay is how long typing goes on before the note is written to disk,
+// leaving the note writes it straight away.
+const saveDelay = time.Second
+
 type errMsg error
 
+// saveMsg writes the note out once saveDelay is up.
+type saveMsg struct{}
+
+type viewState int
+
+const (
+	editing viewState = iota
+	listing
+	browsingHistory
+)
+
+// inputMode is what the line under the note list is asking for.
+type inputMode int
+
+const (
+	noInput inputMode = iota
+	creating
+	renaming
+	deleting
+)
+
 type keymap struct {
-	back key.Binding
+	back      key.Binding
+	listNotes key.Binding
+	open      key.Binding
+	create    key.Binding
+	journal   key.Binding
+	rename    key.Binding
+	delete    key.Binding
+	deleted   key.Binding
+	preview   key.Binding
+	edit      key.Binding
+	toggle    key.Binding
+	promote   key.Binding
+
+	history      key.Binding
+	newer        key.Binding
+	older        key.Binding
+	restore      key.Binding
+	closeHistory key.Binding
+}
+
+// newKeymap binds the actions to keys, by action name as in the config.
+func newKeymap(keys map[string][]string) keymap {
+	return keymap{
+		back:         bindings.New(keys["back"], "back"),
+		listNotes:    bindings.New(keys["list"], "notes"),
+		open:         bindings.New(keys["open"], "open"),
+		create:       bindings.New(keys["new"], "new"),
+		journal:      bindings.New(keys["journal"], "today's journal"),
+		rename:       bindings.New(keys["rename"], "rename"),
+		delete:       bindings.New(keys["delete"], "delete"),
+		deleted:      bindings.New(keys["deleted"], "deleted notes"),
+		preview:      bindings.New(keys["preview"], "preview"),
+		edit:         bindings.New(keys["edit"], "open in $EDITOR"),
+		toggle:       bindings.New(keys["toggle"], "check/uncheck"),
+		promote:      bindings.New(keys["promote"], "unchecked to tasks"),
+		history:      bindings.New(keys["history"], "history"),
+		newer:        bindings.New(keys["newer"], "newer"),
+		older:        bindings.New(keys["older"], "older"),
+		restore:      bindings.New(keys["restore"], "restore"),
+		closeHistory: bindings.New(keys["close_history"], "back"),
+	}
+}
+
+// listHelp is the help shown under the note list.
+func (k keymap) listHelp() []key.Binding {
+	return []key.Binding{k.open, k.create, k.journal, k.rename, k.delete, k.deleted, k.back}
+}
+
+// WithSession sets the task and project of the session under way, which new
+// notes fill templates in with.
+func (m NotesModel) WithSession(task, project string) NotesModel {
+	m.task = task
+	m.project = project
+	return m
+}
+
+// WithKeys rebinds the notes' actions to the keys from the config's
+// [keys.notes] table.
+func (m NotesModel) WithKeys(keys map[string][]string) NotesModel {
+	m.keymap = newKeymap(keys)
+	m.list.AdditionalShortHelpKeys = m.keymap.listHelp
+	return m
 }
 
 type NotesModel struct {
 	textarea textarea.Model
 	err      error
+	notice   string // Shown until the next key press
 	help     help.Model
 	keymap   keymap
+	store    *notes.Store
+	current  string // Name of the note open in the editor
+	saved    string // Content of the current note as last written to disk
+	dirty    bool   // Whether a save is on its way
+	state    viewState
+	list     list.Model
+	input    textinput.Model
+	mode     inputMode
+	// Templates new notes can start from, nil if there is nowhere to keep them
+	templates *notetemplate.Store
+	picker    *templatePicker
+	deleted   *deletedPicker
+	history   *history
+	// Task and project of the session under way, for templates
+	task    string
+	project string
+
+	preview       previewMode
+	viewport      viewport.Model // Rendered note
+	renderer      *glamour.TermRenderer
+	rendererWidth int
+	style         string // Markdown style for the theme
+	dark          bool   // Whether the terminal has a dark background
+	theme         theme.Theme
+	styles        theme.Styles
+	width         int
+	height        int
 }
 
 func (m NotesModel) helpView() string {
 	return "\n" + m.help.ShortHelpView([]key.Binding{
+		m.keymap.listNotes,
+		m.keymap.toggle,
+		m.keymap.promote,
+		m.keymap.preview,
+		m.keymap.history,
+		m.keymap.edit,
 		m.keymap.back,
 	})
 }
 
 func InitialModel() NotesModel {
+	store, err := notes.NewStore(notes.DefaultDir)
+	if err != nil {
+		panic(err)
+	}
 	ti := textarea.New()
 	ti.Placeholder = "Brainstorm..."
-	ti.Focus()
+	// Notes coming back from an external editor can be any length.
+	ti.CharLimit = 0
+	ti.MaxHeight = 0
 
-	return NotesModel{
+	km := newKeymap(config.Default().KeysOf("notes"))
+	m := NotesModel{
 		textarea: ti,
 		err:      nil,
-		keymap: keymap{
-			back: key.NewBinding(
-				key.WithKeys("ctrl", "h"),
-				key.WithHelp("ctrl h", "back"),
-			),
-		},
-		help: help.New(),
+		keymap:   km,
+		help:     help.New(),
+		store:    store,
+		list:     newNoteList(km),
+		input:    textinput.New(),
+		viewport: viewport.New(defaultWidth, defaultHeight),
+		dark:     lipgloss.HasDarkBackground(),
+		width:    defaultWidth,
+		height:   defaultHeight,
+	}
+	m = m.WithTheme(theme.Default())
+	if dir, err := notetemplate.DefaultDir(); err == nil {
+		m.templates, _ = notetemplate.NewStore(dir)
 	}
+	m.layout()
+	m.refreshList()
+
+	// Pick up where the notes were left, or start a fresh note if there are
+	// none yet.
+	if last := store.LastOpened(); last != "" {
+		m.openNote(last)
+	} else {
+		m.leaveNote()
+	}
+	return m
 }
 
 func (m NotesModel) Init() tea.Cmd {
 	return textarea.Blink
 }
 
+func (m *NotesModel) refreshList() {
+	ns, err := m.store.List()
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.list.SetItems(noteItems(ns))
+}
+
+// openNote loads a note into the editor. Journal notes are brought up to
+// date with the break log first.
+func (m *NotesModel) openNote(name string) {
+	if day, ok := journal.Day(name); ok {
+		if _, err := updateJournal(m.store, day); err != nil {
+			m.err = err
+			return
+		}
+	}
+	content, err := m.store.Read(name)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.current = name
+	m.saved = content
+	m.textarea.SetValue(content)
+	m.renderPreview()
+	m.state = editing
+	m.err = m.store.SetLastOpened(name)
+	m.textarea.Focus()
+}
+
+// leaveNote closes the open note, staying in the list to pick another one,
+// or starting a fresh note if there are none left.
+func (m *NotesModel) leaveNote() {
+	m.saved = ""
+	m.textarea.Reset()
+	if len(m.list.Items()) > 0 {
+		m.current = ""
+		m.textarea.Blur()
+		m.state = listing
+		return
+	}
+	m.current = untitled
+	m.state = editing
+	m.textarea.Focus()
+}
+
+func updateJournal(store *notes.Store, day time.Time) (string, error) {
+	logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+	if err != nil {
+		return "", err
+	}
+	return journal.Update(store, day, logger.Entries())
+}
+
+// markDirty has the note saved after saveDelay, unless a save is on its way
+// already.
+func (m *NotesModel) markDirty() tea.Cmd {
+	if m.dirty {
+		return nil
+	}
+	m.dirty = true
+	return tea.Tick(saveDelay, func(time.Time) tea.Msg { return saveMsg{} })
+}
+
+// save writes the current note to disk if it changed since the last save.
+func (m *NotesModel) save() {
+	m.dirty = false
+	if m.current == "" || m.textarea.Value() == m.saved {
+		return
+	}
+	err := m.store.Write(m.current, m.textarea.Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.saved = m.textarea.Value()
+	m.err = m.store.SetLastOpened(m.current)
+}
+
 func (m NotesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		if m.size() == layout.Compact {
+			m.list.SetSize(msg.Width, msg.Height-1)
+		} else {
+			m.list.SetSize(msg.Width, msg.Height-4)
+		}
+		m.list.SetDelegate(noteDelegate(m.theme, m.size()))
+		m.help.Width = msg.Width
+		m.layout()
+		m.layoutHistory()
+	// We handle errors just like any other message
+	case errMsg:
+		m.err = msg
+		return m, nil
+	case saveMsg:
+		m.save()
+		return m, nil
+	case OpenMsg:
+		m.save()
+		m.picker = nil
+		m.deleted = nil
+		m.history = nil
+		m.mode = noInput
+		m.openNote(msg.Name)
+		if line := search.MatchLine(m.textarea.Value(), msg.Query); line >= 0 {
+			m.moveToLine(line)
+		}
+		m.refreshList()
+		return m, textarea.Blink
+	case editor.FinishedMsg:
+		if msg.Err != nil {
+			m.err = msg.Err
+			return m, nil
+		}
+		m.textarea.SetValue(msg.Content)
+		m.renderPreview()
+		m.save()
+		return m, nil
+	}
+	switch m.state {
+	case listing:
+		return m.updateList(msg)
+	case browsingHistory:
+		return m.updateHistory(msg)
+	}
+	return m.updateEditor(msg)
+}
+
+func (m NotesModel) updateEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.notice = ""
 		switch msg.Type {
 		case tea.KeyEsc:
 			if m.textarea.Focused() {
 				m.textarea.Blur()
-				// fmt.Println(m.textarea.Value())
-				// TODO(sam) Create new keybind for saving to file
 			}
 		case tea.KeyCtrlC:
+			m.save()
 			return m, tea.Quit
 		default:
 			if !m.textarea.Focused() {
@@ -74,27 +373,290 @@ func (m NotesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		switch {
 		case key.Matches(msg, m.keymap.back):
+			m.save()
 			return m,
 				func() tea.Msg {
 					return GoBackMsg{}
 				}
+		case key.Matches(msg, m.keymap.listNotes):
+			m.save()
+			m.refreshList()
+			m.state = listing
+			return m, nil
+		case key.Matches(msg, m.keymap.toggle):
+			if m.preview != fullPreview {
+				m.toggleCheckbox()
+				m.renderPreview()
+				m.save()
+			}
+			return m, tea.Batch(cmds...)
+		case key.Matches(msg, m.keymap.promote):
+			m.promoteUnchecked()
+			return m, tea.Batch(cmds...)
+		case key.Matches(msg, m.keymap.history):
+			m.save()
+			m.openHistory()
+			return m, nil
+		case key.Matches(msg, m.keymap.edit):
+			m.save()
+			return m, editor.Open(m.textarea.Value())
+		case key.Matches(msg, m.keymap.preview):
+			m.preview = m.preview.next()
+			m.layout()
+			return m, tea.Batch(cmds...)
+		}
+		// The preview on its own can only be scrolled, not typed into.
+		if m.preview == fullPreview {
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
 		}
-
-	// We handle errors just like any other message
-	case errMsg:
-		m.err = msg
-		return m, nil
 	}
 
 	m.textarea, cmd = m.textarea.Update(msg)
 	cmds = append(cmds, cmd)
+	if m.textarea.Value() != m.saved {
+		if m.preview != noPreview {
+			m.renderPreview()
+		}
+		cmds = append(cmds, m.markDirty())
+	}
 	return m, tea.Batch(cmds...)
 }
 
+func (m NotesModel) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.picker != nil {
+		return m.updatePicker(msg)
+	}
+	if m.deleted != nil {
+		return m.updateDeleted(msg)
+	}
+	if m.mode != noInput {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateInput(msg)
+		}
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && m.list.FilterState() != list.Filtering {
+		selected, hasSelection := m.list.SelectedItem().(item)
+		switch {
+		case msg.Type == tea.KeyCtrlC:
+			return m, tea.Quit
+		case key.Matches(msg, m.keymap.back):
+			return m,
+				func() tea.Msg {
+					return GoBackMsg{}
+				}
+		case key.Matches(msg, m.keymap.open):
+			if hasSelection {
+				m.openNote(selected.note.Name)
+			}
+			return m, textarea.Blink
+		case key.Matches(msg, m.keymap.create):
+			return m, m.askInput(creating, "")
+		case key.Matches(msg, m.keymap.journal):
+			m.openNote(journal.Name(time.Now()))
+			m.refreshList()
+			return m, textarea.Blink
+		case key.Matches(msg, m.keymap.rename):
+			if hasSelection {
+				return m, m.askInput(renaming, selected.note.Name)
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.delete):
+			if hasSelection {
+				return m, m.askInput(deleting, "")
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.deleted):
+			return m, m.pickDeleted()
+		}
+	}
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *NotesModel) askInput(mode inputMode, value string) tea.Cmd {
+	m.mode = mode
+	m.err = nil
+	m.input = textinput.New()
+	m.input.CharLimit = 100
+	switch mode {
+	case creating:
+		m.input.Prompt = "New note: "
+	case renaming:
+		m.input.Prompt = "Rename to: "
+	case deleting:
+		m.input.Prompt = "Delete this note? (y/n) "
+		m.input.CharLimit = 1
+	}
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m NotesModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	selected, _ := m.list.SelectedItem().(item)
+	if m.mode == deleting {
+		if msg.String() == "y" {
+			m.err = m.store.Delete(selected.note.Name)
+			m.refreshList()
+			if m.err == nil && selected.note.Name == m.current {
+				m.leaveNote()
+			}
+		}
+		m.mode = noInput
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = noInput
+		return m, nil
+	case tea.KeyEnter:
+		name := m.input.Value()
+		var err error
+		switch m.mode {
+		case creating:
+			var templates []string
+			if m.templates != nil {
+				templates, _ = m.templates.Names()
+			}
+			if len(templates) == 0 {
+				err = m.store.Create(name)
+				if err == nil {
+					m.openNote(name)
+				}
+				break
+			}
+			if err = notes.ValidateName(name); err == nil && m.store.Exists(name) {
+				err = fmt.Errorf("%w: %q", notes.ErrExists, name)
+			}
+			if err == nil {
+				m.mode = noInput
+				m.picker = newTemplatePicker(name, templates)
+				m.picker.form.WithTheme(m.theme.Form())
+				return m, m.picker.form.Init()
+			}
+		case renaming:
+			err = m.store.Rename(selected.note.Name, name)
+			if err == nil && selected.note.Name == m.current {
+				m.current = name
+			}
+		}
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.mode = noInput
+		m.refreshList()
+		return m, nil
+	}
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m NotesModel) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.Type == tea.KeyEsc {
+		m.picker = nil
+		return m, nil
+	}
+	form, cmd := m.picker.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.picker.form = f
+	}
+	switch m.picker.form.State {
+	case huh.StateCompleted:
+		name := m.picker.name
+		content := ""
+		var err error
+		if m.picker.selected != "" {
+			content, err = m.templates.Render(m.picker.selected, templateValues(m.task, m.project))
+		}
+		m.picker = nil
+		if err == nil {
+			err = m.store.Write(name, content)
+		}
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.openNote(name)
+		m.refreshList()
+		return m, textarea.Blink
+	case huh.StateAborted:
+		m.picker = nil
+	}
+	return m, cmd
+}
+
+func (m NotesModel) errView() string {
+	if m.err == nil {
+		return ""
+	}
+	return "\n" + m.styles.Error.Render(m.err.Error())
+}
+
+// size tells the layout for the window the notes are in.
+func (m NotesModel) size() layout.Size {
+	return layout.Of(m.width, m.height)
+}
+
 func (m NotesModel) View() string {
+	if m.size() == layout.Compact {
+		return lipgloss.NewStyle().
+			MaxWidth(m.width).
+			MaxHeight(m.height).
+			Render(strings.TrimRight(m.view(), "\n"))
+	}
+	return m.view()
+}
+
+func (m NotesModel) view() string {
+	if m.state == browsingHistory {
+		return m.historyView()
+	}
+	if m.state == listing {
+		s := m.list.View()
+		if m.picker != nil {
+			s = m.picker.form.View()
+		}
+		if m.deleted != nil {
+			s = m.deleted.form.View()
+		}
+		if m.mode != noInput {
+			s += "\n" + m.input.View()
+		}
+		return s + m.errView() + "\n\n"
+	}
+
+	status := "saved"
+	if m.textarea.Value() != m.saved {
+		status = "unsaved"
+	}
+	status += m.checklistView()
+	if m.notice != "" {
+		status += " · " + m.notice
+	}
+	if m.size() == layout.Compact {
+		if m.preview == splitPreview {
+			status += " · too narrow to split"
+		}
+		return fmt.Sprintf(
+			"%s %s\n%s%s%s",
+			noteTitleStyle.Render(m.current),
+			m.styles.Muted.Render(status),
+			m.previewView(),
+			m.errView(),
+			m.helpView(),
+		)
+	}
 	return fmt.Sprintf(
-		"Write down thoughts.\n\n%s\n\n%s",
-		m.textarea.View(),
+		"%s %s\n\n%s\n%s\n%s",
+		noteTitleStyle.Render(m.current),
+		m.styles.Muted.Render(status),
+		m.previewView(),
+		m.errView(),
 		m.helpView(),
 	) + "\n\n"
 }
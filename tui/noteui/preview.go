@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package noteui
+
+import (
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewMode is how much of the editor the rendered note takes up.
+type previewMode int
+
+const (
+	noPreview previewMode = iota
+	splitPreview
+	fullPreview
+)
+
+// Size used until the terminal reports its own.
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// chromeHeight is the number of lines around the editor taken by the title,
+// errors and help.
+const chromeHeight = 8
+
+// compactChromeHeight is the same on a compact pane, which keeps to the
+// title and help.
+const compactChromeHeight = 2
+
+// previewStyle is coloured by the theme when rendered.
+var previewStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.NormalBorder()).
+	BorderLeft(true)
+
+// next cycles from the plain editor to a split view, then to the preview on
+// its own.
+func (p previewMode) next() previewMode {
+	return (p + 1) % (fullPreview + 1)
+}
+
+// glamourStyle picks the Markdown style for t, following the terminal
+// background if the theme doesn't say. Whether it is dark has to be asked
+// before the program starts, the terminal can't be queried once it is
+// running.
+func glamourStyle(t theme.Theme, dark bool) string {
+	switch {
+	case t.Markdown != "":
+		return t.Markdown
+	case dark:
+		return "dark"
+	default:
+		return "light"
+	}
+}
+
+// WithTheme restyles the notes in t's colours, and renders the preview in
+// its Markdown style.
+func (m NotesModel) WithTheme(t theme.Theme) NotesModel {
+	m.theme = t
+	m.styles = t.Styles()
+	m.help.Styles = t.Help()
+	m.list.Styles = t.List()
+	m.list.Help.Styles = t.Help()
+	m.list.SetDelegate(noteDelegate(t, m.size()))
+	m.style = glamourStyle(t, m.dark)
+	m.renderer = nil
+	m.renderPreview()
+	return m
+}
+
+// layout sizes the editor and preview to the window for the current preview
+// mode.
+func (m *NotesModel) layout() {
+	chrome := chromeHeight
+	if m.size() == layout.Compact {
+		chrome = compactChromeHeight
+	}
+	height := m.height - chrome
+	if height < 3 {
+		height = 3
+	}
+	editorWidth, previewWidth := m.width, m.width
+	if m.preview == splitPreview && m.size() != layout.Compact {
+		editorWidth = m.width / 2
+		previewWidth = m.width - editorWidth - previewStyle.GetHorizontalFrameSize()
+	}
+	m.textarea.SetWidth(editorWidth)
+	m.textarea.SetHeight(height)
+	m.viewport.Width = previewWidth
+	m.viewport.Height = height
+	m.renderPreview()
+}
+
+// renderPreview renders the note into the preview, keeping the renderer
+// around for as long as the preview keeps its width.
+func (m *NotesModel) renderPreview() {
+	if m.preview == noPreview {
+		return
+	}
+	if m.renderer == nil || m.rendererWidth != m.viewport.Width {
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(m.style),
+			glamour.WithWordWrap(m.viewport.Width-2),
+		)
+		if err != nil {
+			m.err = err
+			return
+		}
+		m.renderer = r
+		m.rendererWidth = m.viewport.Width
+	}
+	out, err := m.renderer.Render(m.textarea.Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.viewport.SetContent(out)
+}
+
+func (m NotesModel) previewView() string {
+	switch m.preview {
+	case splitPreview:
+		// Halves of a compact pane are too narrow to read, the editor
+		// keeps it to itself.
+		if m.size() == layout.Compact {
+			return m.textarea.View()
+		}
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			m.textarea.View(),
+			previewStyle.Copy().BorderForeground(m.theme.Muted).Render(m.viewport.View()),
+		)
+	case fullPreview:
+		return m.viewport.View()
+	default:
+		return m.textarea.View()
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package noteui
+
+import (
+	"time"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/notetemplate"
+	"github.com/charmbracelet/huh"
+)
+
+// templatePicker asks which template a new note starts out from.
+type templatePicker struct {
+	name     string // Note being created
+	selected string
+	form     *huh.Form
+}
+
+func newTemplatePicker(name string, templates []string) *templatePicker {
+	p := templatePicker{name: name}
+	options := []huh.Option[string]{huh.NewOption("Blank", "")}
+	for _, t := range templates {
+		options = append(options, huh.NewOption(t, t))
+	}
+	p.form = huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title("Start " + name + " from").
+			Value(&p.selected).
+			Options(options...),
+	))
+	return &p
+}
+
+// templateValues fills templates in with the task and project of the session
+// under way, and the latest work in progress in the break log.
+func templateValues(task, project string) notetemplate.Values {
+	v := notetemplate.Values{Time: time.Now(), Task: task, Project: project}
+	logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+	if err != nil {
+		return v
+	}
+	entries := logger.Entries()
+	for i := len(entries) - 1; i >= 0 && v.WorkInProgress == ""; i-- {
+		v.WorkInProgress = entries[i].WorkInProgress
+	}
+	return v
+}
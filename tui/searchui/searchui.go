@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package searchui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SamD2021/boba-break/internal/breaklog"
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/internal/notes"
+	"github.com/SamD2021/boba-break/internal/search"
+	"github.com/SamD2021/boba-break/tui/bindings"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Lines taken by the title, input, status and help around the results.
+const chromeHeight = 8
+
+// Lines taken by the input, status and help on a compact pane
+const compactChromeHeight = 3
+
+// Widest the search gets on a large terminal, it is centred in the rest
+const largeWidth = 100
+
+var appStyle = lipgloss.NewStyle().Padding(1, 2)
+
+// Width of the kind of each result, before its title
+const kindWidth = 5
+
+type keymap struct {
+	up   key.Binding
+	down key.Binding
+	open key.Binding
+	back key.Binding
+}
+
+func (k keymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.down, k.open, k.back}
+}
+
+func (k keymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// newKeymap binds the actions to keys, by action name as in the config.
+func newKeymap(keys map[string][]string) keymap {
+	return keymap{
+		up:   bindings.New(keys["up"], "up"),
+		down: bindings.New(keys["down"], "down"),
+		open: bindings.New(keys["open"], "open"),
+		back: bindings.New(keys["back"], "back"),
+	}
+}
+
+type SearchModel struct {
+	input   textinput.Model
+	index   *search.Index
+	results []search.Result
+	cursor  int
+	width   int
+	height  int
+	keymap  keymap
+	help    help.Model
+	err     error
+	styles  theme.Styles
+}
+
+// New indexes the notes and break log as they are now, so each search view
+// sees what was written since the last one.
+func New() SearchModel {
+	input := textinput.New()
+	input.Placeholder = "Search notes, scribbles and the break log..."
+	input.Prompt = "/ "
+	input.Focus()
+	m := SearchModel{
+		input:  input,
+		height: 24,
+		help:   help.New(),
+		keymap: newKeymap(config.Default().KeysOf("search")),
+		styles: theme.Default().Styles(),
+	}
+	store, err := notes.NewStore(notes.DefaultDir)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	logger, err := breaklog.NewFileBreakLogger(breaklog.DefaultFilePath)
+	if err != nil {
+		m.err = err
+		return m
+	}
+	m.index, m.err = search.Build(store, logger.Entries())
+	return m
+}
+
+// WithTheme styles the search in t's colours.
+func (m SearchModel) WithTheme(t theme.Theme) SearchModel {
+	m.styles = t.Styles()
+	m.help.Styles = t.Help()
+	return m
+}
+
+// WithKeys rebinds the search's actions to the keys from the config's
+// [keys.search] table.
+func (m SearchModel) WithKeys(keys map[string][]string) SearchModel {
+	m.keymap = newKeymap(keys)
+	return m
+}
+
+func (m SearchModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.input.Width = m.contentWidth() - len(m.input.Prompt) - 1
+		m.help.Width = m.contentWidth()
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case msg.Type == tea.KeyCtrlC:
+			return m, tea.Quit
+		case key.Matches(msg, m.keymap.back):
+			return m, func() tea.Msg { return GoBackMsg{} }
+		case key.Matches(msg, m.keymap.up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.down):
+			if m.cursor < len(m.results)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case key.Matches(msg, m.keymap.open):
+			if len(m.results) == 0 {
+				return m, nil
+			}
+			selected := SelectedMsg{Result: m.results[m.cursor], Query: m.input.Value()}
+			return m, func() tea.Msg { return selected }
+		}
+	}
+
+	query := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != query && m.index != nil {
+		m.results = m.index.Search(m.input.Value())
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+// contentWidth is the width of the search inside the padding.
+func (m SearchModel) contentWidth() int {
+	switch layout.Of(m.width, m.height) {
+	case layout.Compact:
+		return m.width
+	case layout.Large:
+		return min(m.width-appStyle.GetHorizontalFrameSize(), largeWidth)
+	}
+	return m.width - appStyle.GetHorizontalFrameSize()
+}
+
+// visible returns the range of results that fit on screen, keeping the
+// cursor in view. Each result takes two lines, one on a compact pane.
+func (m SearchModel) visible() (int, int) {
+	n := (m.height - chromeHeight) / 2
+	if layout.Of(m.width, m.height) == layout.Compact {
+		n = m.height - compactChromeHeight
+	}
+	if n < 1 {
+		n = 1
+	}
+	start := 0
+	if m.cursor >= n {
+		start = m.cursor - n + 1
+	}
+	end := start + n
+	if end > len(m.results) {
+		end = len(m.results)
+	}
+	return start, end
+}
+
+func (m SearchModel) View() string {
+	size := layout.Of(m.width, m.height)
+	// Compact panes go without the title, the snippets and the blank lines.
+	compact := size == layout.Compact
+	gap := "\n\n"
+	if compact {
+		gap = "\n"
+	}
+	var b strings.Builder
+	if !compact {
+		b.WriteString(m.styles.Title.Render("Search") + "\n\n")
+	}
+	b.WriteString(m.input.View() + gap)
+	switch {
+	case m.err != nil:
+		b.WriteString(m.styles.Error.Render(m.err.Error()) + "\n")
+	case strings.TrimSpace(m.input.Value()) == "":
+	case len(m.results) == 0:
+		b.WriteString(m.styles.Muted.Render("No matches") + "\n")
+	default:
+		start, end := m.visible()
+		for i := start; i < end; i++ {
+			r := m.results[i]
+			title := r.Title
+			if i == m.cursor {
+				title = m.styles.Selected.Render("> " + title)
+			} else {
+				title = "  " + title
+			}
+			fmt.Fprintf(&b, "%s%s\n", m.styles.Label.Copy().Bold(false).Width(kindWidth).Render(string(r.Kind)), title)
+			if !compact {
+				fmt.Fprintf(&b, "%s  %s\n", strings.Repeat(" ", kindWidth), m.styles.Muted.Render(r.Snippet))
+			}
+		}
+		b.WriteString(m.styles.Muted.Render(fmt.Sprintf(strings.TrimSuffix(gap, "\n")+"%d of %d", m.cursor+1, len(m.results))) + "\n")
+	}
+	if !compact {
+		b.WriteString("\n")
+	}
+	b.WriteString(m.help.View(m.keymap))
+	switch size {
+	case layout.Compact:
+		return lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(m.height).Render(b.String())
+	case layout.Large:
+		// A fixed width keeps the search from moving as the results change.
+		style := appStyle.Copy().Width(m.contentWidth() + appStyle.GetHorizontalFrameSize())
+		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, style.Render(b.String()))
+	}
+	return appStyle.Render(b.String())
+}
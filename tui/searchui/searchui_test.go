@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package searchui
+
+import (
+	"testing"
+
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUpdateKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   tea.KeyMsg
+		want  tea.Msg // What the command returned sends, nil to not check
+		value string  // What is left in the input
+	}{
+		{"ctrl+c quits", tea.KeyMsg{Type: tea.KeyCtrlC}, tea.QuitMsg{}, ""},
+		{"esc goes back", tea.KeyMsg{Type: tea.KeyEsc}, GoBackMsg{}, ""},
+		{"letters are typed", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, nil, "q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := textinput.New()
+			input.Focus()
+			m := SearchModel{input: input, keymap: newKeymap(config.Default().KeysOf("search"))}
+			model, cmd := m.Update(tt.key)
+			if tt.want != nil {
+				if cmd == nil {
+					t.Fatalf("Update() sent nothing, want %#v", tt.want)
+				}
+				if got := cmd(); got != tt.want {
+					t.Errorf("Update() sent %#v, want %#v", got, tt.want)
+				}
+			}
+			if value := model.(SearchModel).input.Value(); value != tt.value {
+				t.Errorf("input = %q, want %q", value, tt.value)
+			}
+		})
+	}
+}
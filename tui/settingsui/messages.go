@@ -0,0 +1,34 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package settingsui
+
+import "github.com/SamD2021/boba-break/internal/config"
+
+type (
+	// GoBackMsg leaves the settings without saving.
+	GoBackMsg struct{}
+	// SavedMsg carries the configuration after the settings were written,
+	// environment overrides and project file included, for the other views
+	// to pick up.
+	SavedMsg struct {
+		Config config.Config
+		// Notice says what only takes effect after a restart, if anything.
+		Notice string
+	}
+)
@@ -0,0 +1,308 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package settingsui
+
+import (
+	"strings"
+
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/SamD2021/boba-break/tui/layout"
+	"github.com/SamD2021/boba-break/tui/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var appStyle = lipgloss.NewStyle().Padding(1, 2)
+
+// Lines taken by the title, path and hint around the form
+const chromeHeight = 5
+
+// Widest the settings get on a large terminal, they are centred in the rest
+const largeWidth = 80
+
+// SettingsModel edits the config file with a form, one page per section.
+type SettingsModel struct {
+	path string
+	// The config as written in the file, to only write what changed
+	file   config.Config
+	values map[string]*string
+	flags  map[string]*bool
+	form   *huh.Form
+	err    error
+	styles theme.Styles
+	// Size of the terminal, 0 until it is reported
+	width, height int
+}
+
+// New loads the config file at path into the form. Environment overrides
+// are left out, they are not what the file says.
+func New(path string) SettingsModel {
+	m := SettingsModel{
+		path:   path,
+		values: map[string]*string{},
+		flags:  map[string]*bool{},
+		styles: theme.Default().Styles(),
+	}
+	m.file, m.err = config.LoadFile(path)
+	if m.err != nil {
+		return m
+	}
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			m.input("timer.focus", "Focus", "like 25m or 1h30m"),
+			m.input("timer.break", "Break", "like 5m"),
+			m.input("timer.long_break", "Long break", "like 15m"),
+			m.input("timer.long_break_every", "Long break every", "number of focus sessions, 0 for never"),
+			m.confirm("timer.auto_start", "Start the next session by itself?"),
+		).Title("Timer"),
+		huh.NewGroup(
+			m.confirm("notifications.enabled", "Send desktop notifications?"),
+			m.input("notifications.focus_title", "Title when focus ends", ""),
+			m.input("notifications.focus_message", "Message when focus ends", ""),
+			m.input("notifications.break_title", "Title when a break ends", ""),
+			m.input("notifications.break_message", "Message when a break ends", ""),
+		).Title("Notifications"),
+		huh.NewGroup(
+			m.confirm("breaks.full_screen", "Give breaks the whole screen?"),
+			m.input("breaks.rotate", "New suggestion every", "like 1m, 0 to keep one per break"),
+			m.selectRoutine(),
+		).Title("Breaks"),
+		huh.NewGroup(
+			m.selectProfile(),
+		).Title("Profile"),
+		huh.NewGroup(
+			m.selectTheme(),
+			m.choose("display.clock", "Clock", "how the time left is shown", config.Clocks),
+			m.choose("display.progress", "Progress", "how much of the session has gone by", config.Progresses),
+			m.input("data_dir", "Data directory", "break log, tasks and notes, used from the next start"),
+			m.input("notes_dir", "Notes directory", "empty for notes in the data directory"),
+		).Title("Appearance and data"),
+	)
+	return m
+}
+
+// WithTheme styles the settings in t's colours.
+func (m SettingsModel) WithTheme(t theme.Theme) SettingsModel {
+	m.styles = t.Styles()
+	if m.form != nil {
+		m.form.WithTheme(t.Form())
+	}
+	return m
+}
+
+// input adds a text field for key, checked as it is typed.
+func (m *SettingsModel) input(key, title, description string) huh.Field {
+	value, _ := m.file.Get(key)
+	m.values[key] = &value
+	file := m.file
+	return huh.NewInput().
+		Title(title).
+		Description(description).
+		Validate(func(s string) error {
+			return file.Check(key, strings.TrimSpace(s))
+		}).
+		Value(&value)
+}
+
+func (m *SettingsModel) confirm(key, title string) huh.Field {
+	value, _ := m.file.Get(key)
+	on := value == "true"
+	m.flags[key] = &on
+	return huh.NewConfirm().
+		Title(title).
+		Affirmative("Yes").
+		Negative("No").
+		Value(&on)
+}
+
+func (m *SettingsModel) selectProfile() huh.Field {
+	value := m.file.Profile
+	m.values["profile"] = &value
+	options := []huh.Option[string]{huh.NewOption("none, use the timer settings", "")}
+	for _, name := range m.file.ProfileNames() {
+		p, _ := m.file.WithProfile(name)
+		options = append(options, huh.NewOption(name+" ("+p.Timer.Summary()+")", name))
+	}
+	return huh.NewSelect[string]().
+		Title("Default profile").
+		Description("the main menu can start any of them").
+		Options(options...).
+		Value(&value)
+}
+
+func (m *SettingsModel) selectRoutine() huh.Field {
+	value := m.file.Breaks.Routine
+	m.values["breaks.routine"] = &value
+	options := []huh.Option[string]{huh.NewOption("none, just suggestions", "")}
+	options = append(options, huh.NewOptions(m.file.RoutineNames()...)...)
+	return huh.NewSelect[string]().
+		Title("Routine").
+		Description("steps to guide each break through").
+		Options(options...).
+		Value(&value)
+}
+
+func (m *SettingsModel) selectTheme() huh.Field {
+	value := m.file.Theme
+	m.values["theme"] = &value
+	return huh.NewSelect[string]().
+		Title("Theme").
+		Description("built in, or a theme file from the themes directory").
+		Options(huh.NewOptions(config.ThemeNames()...)...).
+		Value(&value)
+}
+
+// choose adds a select for key between options.
+func (m *SettingsModel) choose(key, title, description string, options []string) huh.Field {
+	value, _ := m.file.Get(key)
+	m.values[key] = &value
+	return huh.NewSelect[string]().
+		Title(title).
+		Description(description).
+		Options(huh.NewOptions(options...)...).
+		Value(&value)
+}
+
+// value is what the form has for key, written like on the command line.
+func (m SettingsModel) value(key string) string {
+	if on, ok := m.flags[key]; ok {
+		if *on {
+			return "true"
+		}
+		return "false"
+	}
+	if v, ok := m.values[key]; ok {
+		return strings.TrimSpace(*v)
+	}
+	value, _ := m.file.Get(key)
+	return value
+}
+
+// save writes the keys that changed to the file, leaving the rest of it as
+// it is, then reloads it with the project file for the other views.
+func (m SettingsModel) save() (SavedMsg, error) {
+	var saved SavedMsg
+	for _, key := range config.Keys() {
+		value := m.value(key)
+		if old, _ := m.file.Get(key); old == value {
+			continue
+		}
+		if err := config.SetInFile(m.path, key, value); err != nil {
+			return saved, err
+		}
+		if key == "data_dir" || key == "notes_dir" {
+			saved.Notice = "New data directories are used from the next start"
+		}
+	}
+	cfg, _, err := config.LoadForDir(m.path, ".")
+	if err != nil {
+		return saved, err
+	}
+	saved.Config = cfg
+	return saved, nil
+}
+
+func (m SettingsModel) Init() tea.Cmd {
+	if m.form == nil {
+		return nil
+	}
+	return m.form.Init()
+}
+
+// resize fits the form to a width by height terminal. Compact panes drop
+// the padding, the title and the path.
+func (m *SettingsModel) resize(width, height int) {
+	m.width, m.height = width, height
+	if m.form == nil {
+		return
+	}
+	chrome := 1
+	switch layout.Of(width, height) {
+	case layout.Regular:
+		width -= appStyle.GetHorizontalFrameSize()
+		chrome = chromeHeight + appStyle.GetVerticalFrameSize()
+	case layout.Large:
+		width = min(width-appStyle.GetHorizontalFrameSize(), largeWidth)
+		chrome = chromeHeight + appStyle.GetVerticalFrameSize()
+	}
+	m.form.WithWidth(width)
+	// Taller pages scroll instead of running off the screen.
+	if lipgloss.Height(m.form.View()) > height-chrome {
+		m.form.WithHeight(height - chrome)
+	}
+}
+
+func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyEsc:
+			return m, func() tea.Msg { return GoBackMsg{} }
+		}
+	case tea.WindowSizeMsg:
+		m.resize(msg.Width, msg.Height)
+		return m, nil
+	}
+	if m.form == nil {
+		return m, nil
+	}
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	switch m.form.State {
+	case huh.StateAborted:
+		return m, func() tea.Msg { return GoBackMsg{} }
+	case huh.StateCompleted:
+		saved, err := m.save()
+		if err != nil {
+			// Whatever was written before the error stays written.
+			m.err = err
+			m.form = nil
+			return m, nil
+		}
+		return m, func() tea.Msg { return saved }
+	}
+	return m, cmd
+}
+
+func (m SettingsModel) View() string {
+	size := layout.Of(m.width, m.height)
+	var s string
+	if size != layout.Compact {
+		s = m.styles.Title.Render("Settings") + "\n" + m.styles.Muted.Render(m.path) + "\n\n"
+	}
+	if m.form != nil {
+		s += m.form.View()
+	}
+	if m.err != nil {
+		s += "\n" + m.styles.Error.Render(m.err.Error())
+	}
+	s += "\n" + m.styles.Muted.Render("esc to leave without saving")
+	switch size {
+	case layout.Compact:
+		return lipgloss.NewStyle().MaxWidth(m.width).MaxHeight(m.height).Render(s)
+	case layout.Large:
+		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, appStyle.Render(s))
+	}
+	return appStyle.Render(s)
+}
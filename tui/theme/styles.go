@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+package theme
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles are the styles the views have in common.
+type Styles struct {
+	Title     lipgloss.Style // Title bars
+	Header    lipgloss.Style
+	Label     lipgloss.Style
+	Highlight lipgloss.Style
+	Selected  lipgloss.Style
+	Focus     lipgloss.Style
+	Break     lipgloss.Style
+	Error     lipgloss.Style
+	Added     lipgloss.Style
+	Removed   lipgloss.Style
+	Muted     lipgloss.Style
+}
+
+func fg(c lipgloss.TerminalColor) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(c)
+}
+
+// Styles returns the common styles in t's colours.
+func (t Theme) Styles() Styles {
+	s := Styles{
+		Title: lipgloss.NewStyle().
+			Foreground(t.Title).
+			Background(t.TitleBackground).
+			Padding(0, 1),
+		Header:    fg(t.Primary).Bold(true),
+		Label:     fg(t.Accent).Bold(true),
+		Highlight: fg(t.Highlight),
+		Selected:  fg(t.Selected).Bold(true),
+		Focus:     fg(t.Focus),
+		Break:     fg(t.Break),
+		Error:     fg(t.Error),
+		Added:     fg(t.Added),
+		Removed:   fg(t.Removed),
+		Muted:     fg(t.Muted),
+	}
+	if t.Mono {
+		s.Title = s.Title.Reverse(true)
+		s.Highlight = s.Highlight.Bold(true)
+		s.Selected = s.Selected.Underline(true)
+		s.Break = s.Break.Italic(true)
+		s.Error = s.Error.Bold(true)
+		s.Muted = s.Muted.Faint(true)
+	}
+	return s
+}
+
+// Help returns the styles of the key help under the views.
+func (t Theme) Help() help.Styles {
+	key := fg(t.Muted)
+	desc := fg(t.Subtle)
+	if t.Mono {
+		key = key.Bold(true)
+	}
+	return help.Styles{
+		ShortKey:       key,
+		ShortDesc:      desc,
+		ShortSeparator: desc.Copy(),
+		Ellipsis:       desc.Copy(),
+		FullKey:        key.Copy(),
+		FullDesc:       desc.Copy(),
+		FullSeparator:  desc.Copy(),
+	}
+}
+
+// List returns the styles of a list, around its items.
+func (t Theme) List() list.Styles {
+	s := list.DefaultStyles()
+	s.Title = t.Styles().Title
+	s.FilterPrompt = fg(t.Accent)
+	s.FilterCursor = fg(t.Selected)
+	s.DefaultFilterCharacterMatch = lipgloss.NewStyle().Underline(true)
+	s.StatusBar = s.StatusBar.Copy().Foreground(t.Muted)
+	s.StatusEmpty = fg(t.Subtle)
+	s.StatusBarActiveFilter = fg(t.Text)
+	s.StatusBarFilterCount = fg(t.Subtle)
+	s.NoItems = fg(t.Muted)
+	s.ActivePaginationDot = s.ActivePaginationDot.Copy().Foreground(t.Text)
+	s.InactivePaginationDot = s.InactivePaginationDot.Copy().Foreground(t.Subtle)
+	s.ArabicPagination = fg(t.Muted)
+	s.DividerDot = s.DividerDot.Copy().Foreground(t.Subtle)
+	return s
+}
+
+// Items returns the styles of the items of a list.
+func (t Theme) Items() list.DefaultItemStyles {
+	s := list.NewDefaultItemStyles()
+	s.NormalTitle = s.NormalTitle.Copy().Foreground(t.Text)
+	s.NormalDesc = s.NormalDesc.Copy().Foreground(t.Muted)
+	s.SelectedTitle = s.SelectedTitle.Copy().Foreground(t.Selected).BorderForeground(t.Selected)
+	s.SelectedDesc = s.SelectedDesc.Copy().Foreground(t.Selected).BorderForeground(t.Selected)
+	s.DimmedTitle = s.DimmedTitle.Copy().Foreground(t.Muted)
+	s.DimmedDesc = s.DimmedDesc.Copy().Foreground(t.Subtle)
+	if t.Mono {
+		s.SelectedTitle = s.SelectedTitle.Bold(true)
+		s.NormalDesc = s.NormalDesc.Faint(true)
+	}
+	return s
+}
+
+// Form returns the theme of huh forms.
+func (t Theme) Form() *huh.Theme {
+	if t.Mono {
+		return huh.ThemeBase()
+	}
+	f := huh.ThemeCharm()
+	for _, s := range []*huh.FieldStyles{&f.Focused, &f.Blurred} {
+		s.Title = s.Title.Copy().Foreground(t.Primary)
+		s.NoteTitle = s.NoteTitle.Copy().Foreground(t.Primary)
+		s.Description = s.Description.Copy().Foreground(t.Muted)
+		s.ErrorIndicator = s.ErrorIndicator.Copy().Foreground(t.Error)
+		s.ErrorMessage = s.ErrorMessage.Copy().Foreground(t.Error)
+		s.SelectSelector = s.SelectSelector.Copy().Foreground(t.Selected)
+		s.MultiSelectSelector = s.MultiSelectSelector.Copy().Foreground(t.Selected)
+		s.Option = s.Option.Copy().Foreground(t.Text)
+		s.UnselectedOption = s.UnselectedOption.Copy().Foreground(t.Text)
+		s.SelectedOption = s.SelectedOption.Copy().Foreground(t.Accent)
+		s.SelectedPrefix = s.SelectedPrefix.Copy().Foreground(t.Accent)
+		s.FocusedButton = s.FocusedButton.Copy().Foreground(t.Title).Background(t.Selected)
+		s.Next = s.FocusedButton.Copy()
+		s.TextInput.Cursor = s.TextInput.Cursor.Copy().Foreground(t.Accent)
+		s.TextInput.Prompt = s.TextInput.Prompt.Copy().Foreground(t.Selected)
+		s.TextInput.Placeholder = s.TextInput.Placeholder.Copy().Foreground(t.Subtle)
+	}
+	f.Help = t.Help()
+	return f
+}
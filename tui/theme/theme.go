@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2024 Samuel Dasilva
+ *
+ * This file is part of Boba Break.
+ *
+ * Boba Break is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * Boba Break is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with Boba Break. If not, see <https://www.gnu.org/licenses/>.
+ */
+// Package theme holds the colours of the views, from the built in themes or
+// a theme file.
+package theme
+
+import (
+	"os"
+
+	"github.com/SamD2021/boba-break/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a colour for each thing the views show, see config.ThemeColors.
+type Theme struct {
+	Name            string
+	Primary         lipgloss.TerminalColor
+	Accent          lipgloss.TerminalColor
+	Highlight       lipgloss.TerminalColor
+	Selected        lipgloss.TerminalColor
+	Focus           lipgloss.TerminalColor
+	Break           lipgloss.TerminalColor
+	Error           lipgloss.TerminalColor
+	Added           lipgloss.TerminalColor
+	Removed         lipgloss.TerminalColor
+	Text            lipgloss.TerminalColor
+	Muted           lipgloss.TerminalColor
+	Subtle          lipgloss.TerminalColor
+	Title           lipgloss.TerminalColor
+	TitleBackground lipgloss.TerminalColor
+	// Markdown is the style of the note preview, empty to follow the
+	// terminal's background.
+	Markdown string
+	// Mono themes have no colours, bold, underline and reverse stand out
+	// instead.
+	Mono bool
+}
+
+type adaptive = lipgloss.AdaptiveColor
+
+// auto is the theme boba-break always had, picking colours for the
+// terminal's background.
+var auto = Theme{
+	Name:            "auto",
+	Primary:         adaptive{Light: "#5A56E0", Dark: "#7571F9"},
+	Accent:          adaptive{Light: "#02BA84", Dark: "#02BF87"},
+	Highlight:       lipgloss.Color("212"),
+	Selected:        lipgloss.Color("#EE6FF8"),
+	Focus:           lipgloss.Color("#2EF8BB"),
+	Break:           lipgloss.Color("#FF5F87"),
+	Error:           lipgloss.Color("#FE5F86"),
+	Added:           lipgloss.Color("#25A065"),
+	Removed:         lipgloss.Color("#FE5F86"),
+	Text:            adaptive{Light: "#1A1A1A", Dark: "#DDDDDD"},
+	Muted:           lipgloss.Color("240"),
+	Subtle:          adaptive{Light: "#B2B2B2", Dark: "#4A4A4A"},
+	Title:           lipgloss.Color("#FFFDF5"),
+	TitleBackground: lipgloss.Color("#25A065"),
+}
+
+var builtins = map[string]Theme{
+	"auto":  auto,
+	"dark":  auto.side(true),
+	"light": lightTheme(),
+	// Bright colours from the terminal's own palette, nothing dim.
+	"high-contrast": {
+		Name:            "high-contrast",
+		Primary:         lipgloss.Color("15"),
+		Accent:          lipgloss.Color("11"),
+		Highlight:       lipgloss.Color("14"),
+		Selected:        lipgloss.Color("14"),
+		Focus:           lipgloss.Color("10"),
+		Break:           lipgloss.Color("13"),
+		Error:           lipgloss.Color("9"),
+		Added:           lipgloss.Color("10"),
+		Removed:         lipgloss.Color("9"),
+		Text:            lipgloss.Color("15"),
+		Muted:           lipgloss.Color("7"),
+		Subtle:          lipgloss.Color("7"),
+		Title:           lipgloss.Color("0"),
+		TitleBackground: lipgloss.Color("11"),
+	},
+	// The Okabe-Ito palette, blue against orange instead of green against
+	// red.
+	"colorblind": {
+		Name:            "colorblind",
+		Primary:         adaptive{Light: "#0072B2", Dark: "#56B4E9"},
+		Accent:          adaptive{Light: "#009E73", Dark: "#009E73"},
+		Highlight:       adaptive{Light: "#CC79A7", Dark: "#CC79A7"},
+		Selected:        adaptive{Light: "#CC79A7", Dark: "#CC79A7"},
+		Focus:           adaptive{Light: "#0072B2", Dark: "#56B4E9"},
+		Break:           adaptive{Light: "#D55E00", Dark: "#E69F00"},
+		Error:           adaptive{Light: "#D55E00", Dark: "#D55E00"},
+		Added:           adaptive{Light: "#0072B2", Dark: "#56B4E9"},
+		Removed:         adaptive{Light: "#D55E00", Dark: "#E69F00"},
+		Text:            adaptive{Light: "#1A1A1A", Dark: "#DDDDDD"},
+		Muted:           lipgloss.Color("244"),
+		Subtle:          adaptive{Light: "#A0A0A0", Dark: "#5A5A5A"},
+		Title:           lipgloss.Color("#FFFFFF"),
+		TitleBackground: lipgloss.Color("#0072B2"),
+	},
+	"mono": {
+		Name:            "mono",
+		Primary:         lipgloss.NoColor{},
+		Accent:          lipgloss.NoColor{},
+		Highlight:       lipgloss.NoColor{},
+		Selected:        lipgloss.NoColor{},
+		Focus:           lipgloss.NoColor{},
+		Break:           lipgloss.NoColor{},
+		Error:           lipgloss.NoColor{},
+		Added:           lipgloss.NoColor{},
+		Removed:         lipgloss.NoColor{},
+		Text:            lipgloss.NoColor{},
+		Muted:           lipgloss.NoColor{},
+		Subtle:          lipgloss.NoColor{},
+		Title:           lipgloss.NoColor{},
+		TitleBackground: lipgloss.NoColor{},
+		Markdown:        "notty",
+		Mono:            true,
+	},
+}
+
+// lightTheme is auto for light terminals, with the colours that are too
+// bright to read on white darkened.
+func lightTheme() Theme {
+	t := auto.side(false)
+	t.Focus = lipgloss.Color("#00A67E")
+	t.Break = lipgloss.Color("#E0245E")
+	t.Highlight = lipgloss.Color("162")
+	return t
+}
+
+// side makes a theme of the colours t has for dark or light terminals.
+func (t Theme) side(dark bool) Theme {
+	pick := func(c lipgloss.TerminalColor) lipgloss.TerminalColor {
+		a, ok := c.(adaptive)
+		if !ok {
+			return c
+		}
+		if dark {
+			return lipgloss.Color(a.Dark)
+		}
+		return lipgloss.Color(a.Light)
+	}
+	s := t
+	s.Name, s.Markdown = "light", "light"
+	if dark {
+		s.Name, s.Markdown = "dark", "dark"
+	}
+	for _, c := range s.colors() {
+		*c = pick(*c)
+	}
+	return s
+}
+
+// colors returns the colours of t by the names theme files give them, in
+// the order of config.ThemeColors.
+func (t *Theme) colors() []*lipgloss.TerminalColor {
+	return []*lipgloss.TerminalColor{
+		&t.Primary, &t.Accent, &t.Highlight, &t.Selected, &t.Focus,
+		&t.Break, &t.Error, &t.Added, &t.Removed, &t.Text, &t.Muted,
+		&t.Subtle, &t.Title, &t.TitleBackground,
+	}
+}
+
+// Default is the theme used when none is picked.
+func Default() Theme {
+	return Load("auto")
+}
+
+// NoColor tells whether colours were turned off with NO_COLOR, see
+// https://no-color.org.
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// Load returns the built in theme or theme file called name, the mono theme
+// if NO_COLOR is set. A theme that can't be loaded falls back on the
+// default, config.Load has already reported why.
+func Load(name string) Theme {
+	if NoColor() {
+		return builtins["mono"]
+	}
+	if t, ok := builtins[name]; ok {
+		return t
+	}
+	file, err := config.LoadTheme(name)
+	if err != nil || file == nil {
+		return builtins["auto"]
+	}
+	t := builtins[file.Base]
+	t.Name = name
+	if file.Markdown != "" {
+		t.Markdown = file.Markdown
+	}
+	colors := t.colors()
+	for i, role := range config.ThemeColors {
+		c, ok := file.Colors[role]
+		if !ok {
+			continue
+		}
+		if c.Light == c.Dark {
+			*colors[i] = lipgloss.Color(c.Dark)
+		} else {
+			*colors[i] = adaptive{Light: c.Light, Dark: c.Dark}
+		}
+	}
+	return t
+}
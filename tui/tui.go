@@ -21,40 +21,44 @@ package tui
 import (
 	"fmt"
 	"os"
-	"time"
 
+	"github.com/SamD2021/boba-break/internal/config"
 	"github.com/SamD2021/boba-break/tui/breakmanagerui"
 	"github.com/SamD2021/boba-break/tui/mainmenuui"
 	"github.com/SamD2021/boba-break/tui/noteui"
+	"github.com/SamD2021/boba-break/tui/searchui"
+	"github.com/SamD2021/boba-break/tui/settingsui"
+	"github.com/SamD2021/boba-break/tui/theme"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-var style = lipgloss.NewStyle().
-	Bold(true).
-	Foreground(lipgloss.Color("#FAFAFA")).
-	Background(lipgloss.Color("#7D56F4")).
-	PaddingTop(2).
-	PaddingLeft(4).
-	Width(22)
-
 type sessionState int
 
 const (
 	mainMenuView sessionState = iota
 	breakManagerView
 	notesView
-)
-const (
-	workTime  = time.Minute * 25
-	breakTime = time.Minute * 5
+	searchView
+	settingsView
 )
 
 type MainModel struct {
 	mainMenu     tea.Model
 	breakManager tea.Model
 	notes        tea.Model
+	search       tea.Model
+	settings     tea.Model
 	state        sessionState
+	// Config file the settings view edits
+	configPath string
+	// Config as loaded, before any profile is applied
+	cfg config.Config
+	// Timer profile in use, none if empty
+	profile string
+	// Theme of the config, or of the profile in use
+	theme theme.Theme
+	// Last size the terminal reported, for views created after it
+	size tea.WindowSizeMsg
 }
 
 // View implements tea.Model.
@@ -66,18 +70,67 @@ func (m MainModel) View() string {
 		return m.breakManager.View()
 	case notesView:
 		return m.notes.View()
+	case searchView:
+		return m.search.View()
+	case settingsView:
+		return m.settings.View()
 	default:
 		panic("Not implemented yet")
 	}
 }
 
-func initialModel() MainModel {
-	return MainModel{
+func initialModel(cfg config.Config, configPath string) MainModel {
+	m := MainModel{
 		state:        mainMenuView,
-		mainMenu:     mainmenuui.NewModel(),
-		breakManager: breakmanagerui.InitialModel(workTime, breakTime),
+		mainMenu:     mainmenuui.NewModel().WithProfiles(cfg).WithKeys(cfg.KeysOf("menu")),
+		breakManager: breakmanagerui.InitialModel(cfg.Timer.Focus.Duration, cfg.Timer.Break.Duration),
 		notes:        noteui.InitialModel(),
+		configPath:   configPath,
+		cfg:          cfg,
+		profile:      cfg.Profile,
+	}
+	return m.apply()
+}
+
+// apply hands the config, with the profile in use, to the views that can
+// change while running.
+func (m MainModel) apply() MainModel {
+	cfg, err := m.cfg.WithProfile(m.profile)
+	if err != nil {
+		// The profile is gone from the config, go on without it.
+		m.profile = ""
+		cfg = m.cfg
+	}
+	m.theme = theme.Load(cfg.Theme)
+	if menu, ok := m.mainMenu.(mainmenuui.Model); ok {
+		m.mainMenu = menu.WithTheme(m.theme)
+	}
+	if bm, ok := m.breakManager.(breakmanagerui.BreakModel); ok {
+		m.breakManager = bm.WithTheme(m.theme).
+			WithTimer(cfg.Timer).
+			WithProfile(cfg.Profile).
+			WithNotifications(cfg.Notifications).
+			WithDisplay(cfg.Display).
+			WithBreaks(cfg.Breaks, cfg.BreakRoutine()).
+			WithKeys(cfg.KeysOf("break"))
+	}
+	if notes, ok := m.notes.(noteui.NotesModel); ok {
+		m.notes = notes.WithTheme(m.theme).WithKeys(cfg.KeysOf("notes"))
+	}
+	return m
+}
+
+// withSession tells the notes what the break manager is working on, for new
+// notes to fill templates in with.
+func (m MainModel) withSession() MainModel {
+	bm, ok := m.breakManager.(breakmanagerui.BreakModel)
+	if !ok {
+		return m
+	}
+	if notes, ok := m.notes.(noteui.NotesModel); ok {
+		m.notes = notes.WithSession(bm.Task(), bm.Project())
 	}
+	return m
 }
 
 func (m MainModel) Init() tea.Cmd {
@@ -88,15 +141,79 @@ func (m MainModel) Init() tea.Cmd {
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
-	switch msg.(type) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.size = msg
+		// Every view lays itself out to the window, keep the hidden ones
+		// sized so they fit when they show again.
+		if m.state != mainMenuView {
+			m.mainMenu, cmd = m.mainMenu.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.state != breakManagerView {
+			m.breakManager, cmd = m.breakManager.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.state != notesView {
+			m.notes, cmd = m.notes.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	case mainmenuui.SelectedBreakManagerMsg:
 		m.state = breakManagerView
 	case breakmanagerui.BackMsg:
 		m.state = mainMenuView
 	case mainmenuui.SelectedNoteMsg:
 		m.state = notesView
+		m = m.withSession()
 	case noteui.GoBackMsg:
 		m.state = mainMenuView
+	case mainmenuui.SelectedSearchMsg:
+		m.state = searchView
+		m.search = searchui.New().WithKeys(m.cfg.KeysOf("search")).WithTheme(m.theme)
+		if m.size.Width > 0 {
+			m.search, _ = m.search.Update(m.size)
+		}
+		return m, m.search.Init()
+	case searchui.GoBackMsg:
+		m.state = mainMenuView
+	case mainmenuui.SelectedProfileMsg:
+		m.profile = msg.Name
+		m = m.apply()
+		m.state = breakManagerView
+		m.breakManager, cmd = m.breakManager.Update(mainmenuui.SelectedBreakManagerMsg{})
+		return m, cmd
+	case mainmenuui.SelectedSettingsMsg:
+		m.state = settingsView
+		m.settings = settingsui.New(m.configPath).WithTheme(m.theme)
+		if m.size.Width > 0 {
+			m.settings, _ = m.settings.Update(m.size)
+		}
+		return m, m.settings.Init()
+	case settingsui.GoBackMsg:
+		m.state = mainMenuView
+		return m, nil
+	case settingsui.SavedMsg:
+		// A new default profile replaces the one in use, otherwise it
+		// stays.
+		if msg.Config.Profile != m.cfg.Profile {
+			m.profile = msg.Config.Profile
+		}
+		m.cfg = msg.Config
+		m = m.apply()
+		m.state = mainMenuView
+		status := "Settings saved"
+		if msg.Notice != "" {
+			status += ". " + msg.Notice
+		}
+		m.mainMenu, cmd = m.mainMenu.Update(mainmenuui.StatusMsg(status))
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+	case searchui.SelectedMsg:
+		m.state = notesView
+		m = m.withSession()
+		open := noteui.OpenMsg{Name: msg.Result.Note, Query: msg.Query}
+		m.notes, cmd = m.notes.Update(open)
+		return m, cmd
 	}
 	switch m.state {
 	case mainMenuView:
@@ -123,13 +240,18 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.notes = model
 		cmd = newCmd
+	case searchView:
+		m.search, cmd = m.search.Update(msg)
+	case settingsView:
+		m.settings, cmd = m.settings.Update(msg)
 	}
 	cmds = append(cmds, cmd)
 	return m, tea.Batch(cmds...)
 }
 
-func Start() {
-	p := tea.NewProgram(initialModel())
+// Start runs the app with cfg, loaded from the config file at configPath.
+func Start(cfg config.Config, configPath string) {
+	p := tea.NewProgram(initialModel(cfg, configPath))
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)